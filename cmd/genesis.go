@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/p2p-games/wordle/model"
+	"github.com/p2p-games/wordle/wordle"
+)
+
+// Genesis constructs a CLI command to create the Genesis of a new game network.
+func Genesis() *cobra.Command {
+	rules := wordle.DefaultGenesis().Rules
+	var chainID, word string
+
+	cmd := &cobra.Command{
+		Use: "genesis",
+		Short: `Creates the Genesis of a new game network and prints it.
+To join the network, every node saves it as 'genesis.json' in its store, e.g. '~/.wordle/genesis.json'.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, err := model.NewGenesis(chainID, word, rules)
+			if err != nil {
+				return err
+			}
+
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(g)
+		},
+	}
+
+	cmd.Flags().StringVar(&chainID, "chain-id", "", "unique name of the network")
+	cmd.Flags().StringVar(&word, "word", "", "the first word to guess in the network")
+	cmd.Flags().IntVar(&rules.MinWordLen, "min-word-len", rules.MinWordLen, "minimum length of proposed words")
+	cmd.Flags().IntVar(&rules.MaxWordLen, "max-word-len", rules.MaxWordLen, "maximum length of proposed words")
+	cmd.Flags().IntVar(&rules.MaxAttempts, "max-attempts", rules.MaxAttempts, "amount of guesses per word")
+	cmd.Flags().DurationVar(&rules.RoundTimeout, "round-timeout", model.DefaultRoundTimeout,
+		"time after which the proposer of an unsolved word may reveal it, and after twice of which anyone may skip it")
+	cmd.Flags().StringVar(&rules.Dictionary, "dictionary", "",
+		"built-in dictionary, 'en', 'es', 'de' or 'ru', to select words from at random "+
+			"instead of proposers choosing them")
+	cmd.Flags().IntVar(&rules.Lanes, "lanes", 0,
+		fmt.Sprintf("total amount of lanes played in parallel, including the main chain, up to %d, "+
+			"each with its own current word",
+			model.MaxLanes))
+	cmd.Flags().BoolVar(&rules.HardMode, "hard", false,
+		"require every guess to use the hints revealed by the earlier guesses of the player in the round")
+	_ = cmd.MarkFlagRequired("chain-id")
+	_ = cmd.MarkFlagRequired("word")
+	return cmd
+}
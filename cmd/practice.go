@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/spf13/cobra"
+
+	"github.com/p2p-games/wordle/node"
+	"github.com/p2p-games/wordle/node/p2p"
+	"github.com/p2p-games/wordle/wordle"
+)
+
+// Practice constructs a CLI command to play offline against random words from a dictionary.
+func Practice() *cobra.Command {
+	cfg := wordle.DefaultPracticeConfig()
+
+	cmd := &cobra.Command{
+		Use: "practice",
+		Short: `Starts an offline single-player game with random words from a dictionary.
+The results are kept in the store, so they don't need a running Node, but can't be played while it runs.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !node.IsInit(path) {
+				err := node.Init(path, node.Light)
+				if err != nil {
+					return err
+				}
+			}
+
+			store, err := node.OpenStore(path)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			ks, err := store.Keystore()
+			if err != nil {
+				return err
+			}
+			key, err := p2p.Key(ks)
+			if err != nil {
+				return err
+			}
+			ds, err := store.Datastore()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+
+			backend, err := wordle.NewPracticeBackend(ctx, namespace.Wrap(ds, datastore.NewKey("practice")), key, cfg)
+			if err != nil {
+				return err
+			}
+
+			ui := wordle.NewWordleUI(ctx, backend)
+			ui.Run()
+
+			stats := backend.Stats()
+			fmt.Println(stats.String())
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&cfg.Dictionary, "dictionary", cfg.Dictionary,
+		"built-in dictionary to draw words from, 'en', 'es', 'de' or 'ru'")
+	cmd.Flags().IntVar(&cfg.WordLen, "word-len", cfg.WordLen, "length of the words")
+	cmd.Flags().IntVar(&cfg.MaxAttempts, "max-attempts", cfg.MaxAttempts, "amount of guesses per word")
+	return cmd
+}
@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"fmt"
+	"errors"
 	"os/signal"
 	"syscall"
 
@@ -15,6 +15,10 @@ const path = "~/.wordle"
 
 // Start constructs a CLI command to start Node daemon of any type with the given flags.
 func Start(tp node.Type) *cobra.Command {
+	var (
+		daily bool
+		team  string
+	)
 	cmd := &cobra.Command{
 		Use: "start",
 		Short: `Starts Node daemon. First stopping signal gracefully stops the Node and second terminates it.
@@ -23,6 +27,9 @@ Options passed on start override configuration options only on start and are not
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if daily && team != "" {
+				return errors.New("teams can not play the puzzle of the day")
+			}
 			if !node.IsInit(path) {
 				err := node.Init(path, tp)
 				if err != nil {
@@ -47,15 +54,25 @@ Options passed on start override configuration options only on start and are not
 				return err
 			}
 
-			ui := wordle.NewWordleUI(ctx, nd.Wordle, nd.Host.ID().String())
-			ui.Run()
-
-			go func() {
-				hch, _ := nd.Wordle.Guesses(ctx)
-				for hch := range hch {
-					ui.AddDebugItem(fmt.Sprintf("New guess from '%s' \n", hch.PeerID))
+			var backend wordle.GameBackend = nd.Lanes
+			switch {
+			case daily:
+				backend = nd.Daily
+			case team != "":
+				t, err := wordle.NewTeam(team, nd.Wordle, nd.PubSub)
+				if err != nil {
+					return err
 				}
-			}()
+				err = t.Start(ctx)
+				if err != nil {
+					return err
+				}
+				defer t.Stop(ctx) // nolint: errcheck
+				backend = t
+			}
+			ui := wordle.NewWordleUI(ctx, backend)
+			ui.SetChallenges(nd.Challenges)
+			ui.Run()
 
 			<-ctx.Done()
 			cancel() // ensure we stop reading more signals for start context
@@ -70,5 +87,7 @@ Options passed on start override configuration options only on start and are not
 			return store.Close()
 		},
 	}
+	cmd.Flags().BoolVar(&daily, "daily", false, "play the puzzle of the day instead of the chain")
+	cmd.Flags().StringVar(&team, "team", "", "play the chain in the team with the name, sharing guesses and attempts")
 	return cmd
 }
@@ -20,6 +20,8 @@ func init() {
 	rootCmd.AddCommand(
 		lightCmd,
 		fullCmd,
+		cmd.Genesis(),
+		cmd.Practice(),
 	)
 }
 
@@ -35,7 +37,7 @@ func run() error {
 }
 
 var rootCmd = &cobra.Command{
-	Use:  "wordle [light|full]",
+	Use:  "wordle [light|full|genesis|practice]",
 	Args: cobra.NoArgs,
 	CompletionOptions: cobra.CompletionOptions{
 		DisableDefaultCmd: true,
@@ -0,0 +1,62 @@
+package dictionary
+
+import (
+	"fmt"
+	"strings"
+)
+
+// alphabets of the languages with built-in dictionaries, keyed by the dictionary name.
+var alphabets = map[string]string{
+	"en": "abcdefghijklmnopqrstuvwxyz",
+	"es": "abcdefghijklmnñopqrstuvwxyzáéíóúü",
+	"de": "abcdefghijklmnopqrstuvwxyzäöüß",
+	"ru": "абвгдеёжзийклмнопрстуфхцчшщъыьэюя",
+}
+
+// languages are the names of the built-in languages, in the order of preference for words fitting several alphabets.
+var languages = []string{"en", "es", "de", "ru"}
+
+// Languages returns the names of the built-in languages, e.g. "en".
+func Languages() []string {
+	return append([]string(nil), languages...)
+}
+
+// Alphabet is the set of lowercase letters of a language.
+type Alphabet struct {
+	letters []string
+	index   map[string]bool
+}
+
+// GetAlphabet returns the Alphabet of the language with the given 'name', e.g. "en".
+func GetAlphabet(name string) (*Alphabet, error) {
+	s, ok := alphabets[name]
+	if !ok {
+		return nil, fmt.Errorf("dictionary: unknown alphabet '%s'", name)
+	}
+
+	a := &Alphabet{letters: Letters(s), index: make(map[string]bool)}
+	for _, l := range a.letters {
+		a.index[l] = true
+	}
+	return a, nil
+}
+
+// Letters returns the letters of the Alphabet in order.
+func (a *Alphabet) Letters() []string {
+	return a.letters
+}
+
+// Contains reports whether the 'word' consists of the Alphabet letters only.
+func (a *Alphabet) Contains(word string) bool {
+	for _, l := range Letters(word) {
+		if !a.index[l] {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the letters of the Alphabet as a single string.
+func (a *Alphabet) String() string {
+	return strings.Join(a.letters, "")
+}
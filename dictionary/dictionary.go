@@ -0,0 +1,133 @@
+// Package dictionary provides the word lists networks agree to select words from.
+package dictionary
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+//go:embed words/*.txt
+var files embed.FS
+
+// Dictionary is an ordered list of words of a language.
+type Dictionary struct {
+	name     string
+	alphabet *Alphabet
+	words    []string
+	index    map[string]int
+	byLen    map[int][]string // words of every length in letters
+}
+
+var (
+	cacheLk sync.Mutex
+	cache   = make(map[string]*Dictionary)
+)
+
+// Get returns the built-in Dictionary with the given 'name', e.g. "en".
+// The name is also the language of the Dictionary, which defines its Alphabet.
+func Get(name string) (*Dictionary, error) {
+	cacheLk.Lock()
+	defer cacheLk.Unlock()
+	if d, ok := cache[name]; ok {
+		return d, nil
+	}
+
+	data, err := files.ReadFile("words/" + name + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("dictionary: unknown dictionary '%s'", name)
+	}
+
+	alphabet, err := GetAlphabet(name)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Dictionary{name: name, alphabet: alphabet, index: make(map[string]int), byLen: make(map[int][]string)}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		word := Normalize(s.Text())
+		if word == "" {
+			continue
+		}
+		if !alphabet.Contains(word) {
+			return nil, fmt.Errorf("dictionary: word '%s' in '%s' is out of the alphabet", word, name)
+		}
+		if _, ok := d.index[word]; ok {
+			return nil, fmt.Errorf("dictionary: duplicate word '%s' in '%s'", word, name)
+		}
+
+		d.index[word] = len(d.words)
+		d.words = append(d.words, word)
+		d.byLen[Len(word)] = append(d.byLen[Len(word)], word)
+	}
+	if len(d.words) == 0 {
+		return nil, fmt.Errorf("dictionary: '%s' is empty", name)
+	}
+
+	cache[name] = d
+	return d, nil
+}
+
+// Name returns the name of the Dictionary.
+func (d *Dictionary) Name() string {
+	return d.name
+}
+
+// Alphabet returns the Alphabet of the Dictionary's language.
+func (d *Dictionary) Alphabet() *Alphabet {
+	return d.alphabet
+}
+
+// Len returns the amount of words in the Dictionary.
+func (d *Dictionary) Len() int {
+	return len(d.words)
+}
+
+// Word returns the word at the index 'i'.
+func (d *Dictionary) Word(i int) string {
+	return d.words[i]
+}
+
+// Words returns the words of 'n' letters in the order of the Dictionary, or all the words, if 'n' is zero.
+// The slice must not be modified.
+func (d *Dictionary) Words(n int) []string {
+	if n == 0 {
+		return d.words
+	}
+	return d.byLen[n]
+}
+
+// Lens returns the lengths of the words in letters, from the shortest.
+func (d *Dictionary) Lens() []int {
+	lens := make([]int, 0, len(d.byLen))
+	for l := range d.byLen {
+		lens = append(lens, l)
+	}
+	sort.Ints(lens)
+	return lens
+}
+
+// Contains reports whether the 'word' is in the Dictionary.
+func (d *Dictionary) Contains(word string) bool {
+	_, ok := d.index[Normalize(word)]
+	return ok
+}
+
+// Bounds returns the lengths of the shortest and the longest words in letters.
+func (d *Dictionary) Bounds() (min, max int) {
+	min = Len(d.words[0])
+	for _, w := range d.words {
+		l := Len(w)
+		if l < min {
+			min = l
+		}
+		if l > max {
+			max = l
+		}
+	}
+	return min, max
+}
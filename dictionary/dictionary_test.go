@@ -0,0 +1,81 @@
+package dictionary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	d, err := Get("en")
+	require.NoError(t, err)
+	assert.Equal(t, "en", d.Name())
+	assert.True(t, d.Contains("hello"))
+	assert.False(t, d.Contains("qwxyz"))
+	assert.Equal(t, "able", d.Word(0))
+
+	min, max := d.Bounds()
+	assert.Equal(t, 4, min)
+	assert.Equal(t, 7, max)
+	assert.Equal(t, []int{4, 5, 6, 7}, d.Lens())
+	assert.Len(t, d.Words(0), d.Len())
+	total := 0
+	for _, l := range d.Lens() {
+		words := d.Words(l)
+		require.NotEmpty(t, words)
+		for _, w := range words {
+			require.Equal(t, l, Len(w), w)
+		}
+		total += len(words)
+	}
+	assert.Equal(t, d.Len(), total)
+	assert.Contains(t, d.Words(6), "planet")
+	assert.Empty(t, d.Words(8))
+
+	same, err := Get("en")
+	require.NoError(t, err)
+	assert.Same(t, d, same)
+
+	_, err = Get("klingon")
+	assert.Error(t, err)
+}
+
+func TestLanguages(t *testing.T) {
+	tests := []struct {
+		name, word, foreign string
+	}{
+		{"es", "cañón", "größe"},
+		{"de", "größe", "cañón"},
+		{"ru", "щётка", "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Get(tt.name)
+			require.NoError(t, err)
+			assert.True(t, d.Contains(tt.word))
+
+			min, max := d.Bounds()
+			assert.Equal(t, 4, min)
+			assert.Equal(t, 7, max)
+			assert.Equal(t, []int{4, 5, 6, 7}, d.Lens())
+
+			assert.True(t, d.Alphabet().Contains(tt.word))
+			assert.False(t, d.Alphabet().Contains(tt.foreign))
+		})
+	}
+}
+
+func TestLetters(t *testing.T) {
+	decomposed := "can\u0303o\u0301n"
+	assert.Equal(t, "cañón", Normalize(decomposed))
+	assert.Equal(t, []string{"c", "a", "ñ", "ó", "n"}, Letters(decomposed))
+	assert.Equal(t, 5, Len(decomposed))
+	assert.Equal(t, 5, Len("щётка"))
+	assert.Equal(t, 3, Len("x\u0301yz"))
+
+	d, err := Get("es")
+	require.NoError(t, err)
+	assert.True(t, d.Contains(decomposed))
+}
@@ -0,0 +1,28 @@
+package dictionary
+
+import (
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize brings the 'word' to the Unicode Normalization Form C, so that the same letters typed
+// differently, e.g. precomposed 'ñ' and 'n' with a combining tilde, are the same word.
+func Normalize(word string) string {
+	return norm.NFC.String(word)
+}
+
+// Letters splits the normalized 'word' into letters, i.e. user-perceived characters (grapheme clusters),
+// which may consist of multiple runes.
+func Letters(word string) []string {
+	var letters []string
+	g := uniseg.NewGraphemes(Normalize(word))
+	for g.Next() {
+		letters = append(letters, g.Str())
+	}
+	return letters
+}
+
+// Len returns the amount of letters in the 'word'.
+func Len(word string) int {
+	return uniseg.GraphemeClusterCount(Normalize(word))
+}
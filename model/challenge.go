@@ -0,0 +1,277 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/dictionary"
+)
+
+// challengePrefix separates challenge signatures from any other signatures of the same key.
+const challengePrefix = "wordle/challenge/"
+
+// Challenge commits to a word one player challenges another one to guess.
+type Challenge struct {
+	// From and To are the PeerIDs of the challenger and the challenged player.
+	From, To string
+	// Word is the commitment to the word to guess.
+	Word *Word
+	// Language of the word, whose alphabet the guesses are written in, e.g. "en".
+	Language string
+	// MaxAttempts is the amount of guesses the challenged player has.
+	MaxAttempts int
+	// Time the challenge was created.
+	Time int64
+	// PubKey is the marshalled public key of the challenger, matching From.
+	PubKey []byte
+	// Signature of the challenger over the Challenge without the Signature.
+	Signature []byte `json:",omitempty"`
+}
+
+// NewChallenge challenges the player 'to' to guess the 'word' of the 'language' in 'maxAttempts',
+// signed with the challenger's 'key'.
+func NewChallenge(to, word, language string, maxAttempts, saltLen int, key crypto.PrivKey) (*Challenge, error) {
+	alphabet, err := dictionary.GetAlphabet(language)
+	if err != nil {
+		return nil, err
+	}
+	if !alphabet.Contains(word) {
+		return nil, fmt.Errorf("model: the word must consist of letters '%s'", alphabet)
+	}
+
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := crypto.MarshalPublicKey(key.GetPublic())
+	if err != nil {
+		return nil, err
+	}
+	prop, err := NewProposal(word, saltLen)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Challenge{
+		From:        id.String(),
+		To:          to,
+		Word:        prop,
+		Language:    language,
+		MaxAttempts: maxAttempts,
+		Time:        time.Now().Unix(),
+		PubKey:      pub,
+	}
+
+	msg, err := c.signingBytes()
+	if err != nil {
+		return nil, err
+	}
+	c.Signature, err = key.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Hash computes the hash of the Challenge.
+func (c *Challenge) Hash() (multihash.Multihash, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	return multihash.Encode(hash[:], multihash.SHA2_256)
+}
+
+// Header returns the Header the challenged player plays the Challenge in, proposed by the challenger.
+func (c *Challenge) Header() *Header {
+	return &Header{
+		Height:   1,
+		Guess:    &Word{Chars: []*Char{}},
+		Proposal: c.Word,
+		PeerID:   c.From,
+		Time:     c.Time,
+	}
+}
+
+// Validate checks that the Challenge is well-formed within the 'rules' and signed by the challenger.
+func (c *Challenge) Validate(rules Rules) error {
+	if c.To == "" {
+		return errors.New("model: challenged player is missing")
+	}
+	if c.Word == nil {
+		return errors.New("model: challenge word is missing")
+	}
+	if l := len(c.Word.Chars); l < rules.MinWordLen || l > rules.MaxWordLen {
+		return fmt.Errorf("model: challenge word length %d is out of bounds", l)
+	}
+	if rules.Dictionary != "" && c.Language != rules.Dictionary {
+		return fmt.Errorf("model: challenge language '%s' is not the one of the dictionary", c.Language)
+	}
+	if _, err := dictionary.GetAlphabet(c.Language); err != nil {
+		return fmt.Errorf("model: challenge language: %w", err)
+	}
+	for _, ch := range c.Word.Chars {
+		if ch == nil || len(ch.Salt) < MinSaltLength || len(ch.Salt) > MaxSaltLength {
+			return errors.New("model: invalid challenge word salt")
+		}
+	}
+	if c.MaxAttempts <= 0 || c.MaxAttempts > rules.MaxAttempts {
+		return fmt.Errorf("model: challenge attempts %d out of [1, %d]", c.MaxAttempts, rules.MaxAttempts)
+	}
+
+	msg, err := c.signingBytes()
+	if err != nil {
+		return err
+	}
+	return verifySignature(c.PubKey, c.From, msg, c.Signature)
+}
+
+// ChallengeResult is the signed result of a Challenge. It reveals the guesses, so anyone can check their scoring
+// against the Challenge.
+type ChallengeResult struct {
+	Challenge *Challenge
+	// Guesses of the challenged player.
+	Guesses []string
+	// PubKey is the marshalled public key of the challenged player, matching Challenge.To.
+	PubKey []byte
+	// Signature of the challenged player over the result without the Signature.
+	Signature []byte `json:",omitempty"`
+}
+
+// NewChallengeResult signs the 'guesses' of the Challenge 'c' with the challenged player's 'key'.
+func NewChallengeResult(c *Challenge, guesses []string, key crypto.PrivKey) (*ChallengeResult, error) {
+	pub, err := crypto.MarshalPublicKey(key.GetPublic())
+	if err != nil {
+		return nil, err
+	}
+
+	r := &ChallengeResult{Challenge: c, Guesses: guesses, PubKey: pub}
+	msg, err := r.signingBytes()
+	if err != nil {
+		return nil, err
+	}
+	r.Signature, err = key.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Grid returns a row of marks for every guess, as in DailyResults.
+func (r *ChallengeResult) Grid() []string {
+	grid := make([]string, len(r.Guesses))
+	for i, g := range r.Guesses {
+		grid[i] = GradeWord(g, r.Challenge.Word)
+	}
+	return grid
+}
+
+// Solved reports whether the last guess solved the Challenge.
+func (r *ChallengeResult) Solved() bool {
+	return len(r.Guesses) > 0 && isSolution(r.Guesses[len(r.Guesses)-1], r.Challenge.Word)
+}
+
+// Validate checks that the ChallengeResult finishes the valid Challenge within the 'rules'
+// and is signed by the challenged player.
+func (r *ChallengeResult) Validate(rules Rules) error {
+	if r.Challenge == nil {
+		return errors.New("model: challenge is missing")
+	}
+	err := r.Challenge.Validate(rules)
+	if err != nil {
+		return err
+	}
+
+	if len(r.Guesses) == 0 || len(r.Guesses) > r.Challenge.MaxAttempts {
+		return fmt.Errorf("model: %d guesses out of [1, %d]", len(r.Guesses), r.Challenge.MaxAttempts)
+	}
+	for i, g := range r.Guesses {
+		if dictionary.Len(g) != len(r.Challenge.Word.Chars) {
+			return fmt.Errorf("model: guess %d of a wrong length", i)
+		}
+		if isSolution(g, r.Challenge.Word) && i != len(r.Guesses)-1 {
+			return fmt.Errorf("model: guesses after solving the challenge")
+		}
+	}
+	if !r.Solved() && len(r.Guesses) != r.Challenge.MaxAttempts {
+		return fmt.Errorf("model: unsolved result before running out of attempts")
+	}
+
+	msg, err := r.signingBytes()
+	if err != nil {
+		return err
+	}
+	return verifySignature(r.PubKey, r.Challenge.To, msg, r.Signature)
+}
+
+// GradeWord marks every letter of the 'guess' against the committed 'target' word, as Grade does for plaintexts.
+func GradeWord(guess string, target *Word) string {
+	letters := dictionary.Letters(guess)
+	marks := make([]rune, len(letters))
+	used := make([]bool, len(target.Chars))
+	for i, l := range letters {
+		marks[i] = MarkAbsent
+		if i < len(target.Chars) && hashChar(l, target.Chars[i].Salt) == target.Chars[i].Hash {
+			marks[i], used[i] = MarkCorrect, true
+		}
+	}
+	for i, l := range letters {
+		if marks[i] != MarkAbsent {
+			continue
+		}
+		for j, ch := range target.Chars {
+			if !used[j] && hashChar(l, ch.Salt) == ch.Hash {
+				marks[i], used[j] = MarkPresent, true
+				break
+			}
+		}
+	}
+	return string(marks)
+}
+
+func (c *Challenge) signingBytes() ([]byte, error) {
+	cp := *c
+	cp.Signature = nil
+	data, err := json.Marshal(&cp)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(challengePrefix), data...), nil
+}
+
+func (r *ChallengeResult) signingBytes() ([]byte, error) {
+	cp := *r
+	cp.Signature = nil
+	data, err := json.Marshal(&cp)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(challengePrefix), data...), nil
+}
+
+// verifySignature checks that the 'signature' over the 'msg' is made by the marshalled key 'pubKey' of the peer 'id'.
+func verifySignature(pubKey []byte, id string, msg, signature []byte) error {
+	pub, err := crypto.UnmarshalPublicKey(pubKey)
+	if err != nil {
+		return fmt.Errorf("model: public key: %w", err)
+	}
+	pid, err := peer.IDFromPublicKey(pub)
+	if err != nil || pid.String() != id {
+		return fmt.Errorf("model: public key does not match peer %s", id)
+	}
+
+	ok, err := pub.Verify(msg, signature)
+	if err != nil || !ok {
+		return fmt.Errorf("model: invalid signature of peer %s", id)
+	}
+	return nil
+}
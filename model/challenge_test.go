@@ -0,0 +1,80 @@
+package model
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGradeWord(t *testing.T) {
+	for _, tt := range []struct{ guess, target string }{
+		{"hello", "hello"},
+		{"world", "hello"},
+		{"ollie", "hello"},
+		{"lllll", "hello"},
+		{"niño", "ñino"},
+	} {
+		target, err := NewProposal(tt.target, DefaultSaltLength)
+		require.NoError(t, err)
+		assert.Equal(t, Grade(tt.guess, tt.target), GradeWord(tt.guess, target), "%s against %s", tt.guess, tt.target)
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	rules := Rules{MinWordLen: 3, MaxWordLen: 10, MaxAttempts: 3}
+	fromKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	toKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	to, err := peer.IDFromPrivateKey(toKey)
+	require.NoError(t, err)
+
+	ch, err := NewChallenge(to.String(), "hello", "en", 2, DefaultSaltLength, fromKey)
+	require.NoError(t, err)
+	require.NoError(t, ch.Validate(rules))
+	assert.Equal(t, ch.From, ch.Header().PeerID)
+
+	tampered := *ch
+	tampered.MaxAttempts = 3
+	assert.Error(t, tampered.Validate(rules))
+	tampered = *ch
+	tampered.Language = "es"
+	assert.Error(t, tampered.Validate(rules))
+
+	// words of any built-in language, unless the network has a dictionary
+	_, err = NewChallenge(to.String(), "hello", "xx", 2, DefaultSaltLength, fromKey)
+	assert.Error(t, err)
+	_, err = NewChallenge(to.String(), "cañón", "en", 2, DefaultSaltLength, fromKey)
+	assert.Error(t, err)
+	es, err := NewChallenge(to.String(), "cañón", "es", 2, DefaultSaltLength, fromKey)
+	require.NoError(t, err)
+	require.NoError(t, es.Validate(rules))
+	rules.Dictionary = "en"
+	assert.Error(t, es.Validate(rules))
+	require.NoError(t, ch.Validate(rules))
+	rules.Dictionary = ""
+
+	// anyone can check the scoring of the result
+	res, err := NewChallengeResult(ch, []string{"world", "hello"}, toKey)
+	require.NoError(t, err)
+	require.NoError(t, res.Validate(rules))
+	assert.True(t, res.Solved())
+	assert.Equal(t, []string{".y.g.", "ggggg"}, res.Grid())
+
+	// only the challenged player signs results
+	res, err = NewChallengeResult(ch, []string{"world", "hello"}, fromKey)
+	require.NoError(t, err)
+	assert.Error(t, res.Validate(rules))
+
+	// unsolved results have all the attempts
+	res, err = NewChallengeResult(ch, []string{"world"}, toKey)
+	require.NoError(t, err)
+	assert.Error(t, res.Validate(rules))
+	res, err = NewChallengeResult(ch, []string{"hello", "world"}, toKey)
+	require.NoError(t, err)
+	assert.Error(t, res.Validate(rules))
+}
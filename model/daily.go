@@ -0,0 +1,217 @@
+package model
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/dictionary"
+)
+
+// dailyPrefix separates daily result signatures from any other signatures of the same key.
+const dailyPrefix = "wordle/daily/"
+
+// dayLayout formats days of daily puzzles.
+const dayLayout = "2006-01-02"
+
+// Marks of the letters in a row of the DailyResult grid.
+const (
+	// MarkAbsent means the letter is not in the word.
+	MarkAbsent = '.'
+	// MarkPresent means the letter is in the word, but at another position.
+	MarkPresent = 'y'
+	// MarkCorrect means the letter is at its position in the word.
+	MarkCorrect = 'g'
+)
+
+// Day returns the UTC date of the time 't', identifying the daily puzzle played at that time.
+func Day(t time.Time) string {
+	return t.UTC().Format(dayLayout)
+}
+
+// DayStart returns the time the daily puzzle of the 'day' starts.
+func DayStart(day string) (time.Time, error) {
+	return time.Parse(dayLayout, day)
+}
+
+// DailyWord picks the word of the 'day' from the dictionary 'dict' for the network with the 'genesis' hash.
+// Every node derives the same word, without any communication.
+func DailyWord(dict *dictionary.Dictionary, genesis multihash.Multihash, day string) string {
+	seed := append(append([]byte(dailyPrefix), genesis...), day...)
+	return dict.Word(selectionIndex(seed, dict.Len()))
+}
+
+// DailyProposal commits to the 'word' of the 'day' for the network with the 'genesis' hash.
+// The salts are derived from the seed, so that every node commits to the same Word.
+func DailyProposal(genesis multihash.Multihash, day, word string) (*Word, error) {
+	salts := make([]string, dictionary.Len(word))
+	for i := range salts {
+		salts[i] = fmt.Sprintf("%s%s/%s/%d", dailyPrefix, genesis.B58String(), day, i)
+	}
+
+	chars, err := getChars(word, salts)
+	if err != nil {
+		return nil, err
+	}
+	return &Word{Chars: chars}, nil
+}
+
+// Grade marks every letter of the 'guess' against the 'target' word of the same length.
+// Repeated letters are marked present only as many times as they are in the target and not yet correct.
+func Grade(guess, target string) string {
+	g, t := dictionary.Letters(guess), dictionary.Letters(target)
+	marks := make([]rune, len(g))
+	left := make(map[string]int, len(t))
+	for i := range g {
+		marks[i] = MarkAbsent
+		switch {
+		case i >= len(t):
+		case g[i] == t[i]:
+			marks[i] = MarkCorrect
+		default:
+			left[t[i]]++
+		}
+	}
+	for i := range g {
+		if marks[i] == MarkAbsent && left[g[i]] > 0 {
+			marks[i] = MarkPresent
+			left[g[i]]--
+		}
+	}
+	return string(marks)
+}
+
+// DailyResult is the signed result of a player in the daily puzzle.
+// It reveals the marks of the guesses, but not the guesses themselves, so it can be shared right away.
+// The result is self-reported: the player grades its own guesses, and nobody opens the Commitment to check them,
+// so the signature only binds the player to the Grid it claims.
+type DailyResult struct {
+	// Day of the puzzle.
+	Day string
+	// PeerID of the player.
+	PeerID string
+	// Grid has a row of marks for every guess.
+	Grid []string
+	// Solved is true if the last guess is correct.
+	Solved bool
+	// Commitment is the hash of the salted guesses, binding the player to the guesses behind the Grid,
+	// which the player may open to prove the Grid.
+	Commitment []byte
+	// PubKey is the marshalled public key of the player, matching its PeerID.
+	PubKey []byte
+	// Signature of the player over the result without the Signature.
+	Signature []byte `json:",omitempty"`
+}
+
+// NewDailyResult signs the result of the 'guesses' of the 'target' word of the 'day' with the player's 'key'.
+// It returns the salt of the Commitment, which is needed to open it.
+func NewDailyResult(day, target string, guesses []string, key crypto.PrivKey) (*DailyResult, []byte, error) {
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub, err := crypto.MarshalPublicKey(key.GetPublic())
+	if err != nil {
+		return nil, nil, err
+	}
+	salt := make([]byte, 32)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+
+	r := &DailyResult{
+		Day:        day,
+		PeerID:     id.String(),
+		Grid:       make([]string, len(guesses)),
+		Commitment: DailyCommitment(salt, guesses),
+		PubKey:     pub,
+	}
+	for i, g := range guesses {
+		r.Grid[i] = Grade(g, target)
+	}
+	r.Solved = len(guesses) > 0 && dictionary.Normalize(guesses[len(guesses)-1]) == dictionary.Normalize(target)
+
+	msg, err := r.signingBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	r.Signature, err = key.Sign(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, salt, nil
+}
+
+// DailyCommitment hashes the 'guesses' with the 'salt'.
+func DailyCommitment(salt []byte, guesses []string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	for _, g := range guesses {
+		h.Write([]byte(dictionary.Normalize(g)))
+		h.Write([]byte{0})
+	}
+	return h.Sum(nil)
+}
+
+// Attempts is the amount of guesses the player made.
+func (r *DailyResult) Attempts() int {
+	return len(r.Grid)
+}
+
+// Validate checks that the DailyResult is well-formed within the 'rules' and signed by its player.
+func (r *DailyResult) Validate(rules Rules) error {
+	if _, err := DayStart(r.Day); err != nil {
+		return fmt.Errorf("model: invalid day '%s'", r.Day)
+	}
+	if len(r.Grid) == 0 || len(r.Grid) > rules.MaxAttempts {
+		return fmt.Errorf("model: %d attempts out of [1, %d]", len(r.Grid), rules.MaxAttempts)
+	}
+	if len(r.Commitment) != sha256.Size {
+		return fmt.Errorf("model: invalid commitment length %d", len(r.Commitment))
+	}
+
+	// only the last row may be all correct, and only if solved
+	for i, row := range r.Grid {
+		marks := string([]rune{MarkAbsent, MarkPresent, MarkCorrect})
+		if row == "" || len(row) != len(r.Grid[0]) || strings.Trim(row, marks) != "" {
+			return fmt.Errorf("model: invalid grid row '%s'", row)
+		}
+		last := i == len(r.Grid)-1
+		if solved := strings.Count(row, string(MarkCorrect)) == len(row); solved != (last && r.Solved) {
+			return fmt.Errorf("model: grid row %d does not match the solved flag", i)
+		}
+	}
+	if !r.Solved && len(r.Grid) != rules.MaxAttempts {
+		return fmt.Errorf("model: unsolved result before running out of attempts")
+	}
+
+	msg, err := r.signingBytes()
+	if err != nil {
+		return err
+	}
+	return verifySignature(r.PubKey, r.PeerID, msg, r.Signature)
+}
+
+// Opens checks that the 'guesses' with the 'salt' match the Commitment.
+// The protocol does not open Commitments, so it is up to the player to reveal them, e.g. to settle a dispute.
+func (r *DailyResult) Opens(salt []byte, guesses []string) bool {
+	return bytes.Equal(r.Commitment, DailyCommitment(salt, guesses))
+}
+
+func (r *DailyResult) signingBytes() ([]byte, error) {
+	cp := *r
+	cp.Signature = nil
+	data, err := json.Marshal(&cp)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(dailyPrefix), data...), nil
+}
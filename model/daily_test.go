@@ -0,0 +1,82 @@
+package model
+
+import (
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/p2p-games/wordle/dictionary"
+)
+
+func TestGrade(t *testing.T) {
+	tests := []struct {
+		guess, target, marks string
+	}{
+		{"hello", "hello", "ggggg"},
+		{"world", "hello", ".y.g."},
+		{"ollie", "hello", "yyg.y"},
+		{"lllll", "hello", "..gg."},
+		{"niño", "ñino", "ygyg"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.marks, Grade(tt.guess, tt.target), "%s against %s", tt.guess, tt.target)
+	}
+}
+
+func TestDailyWord(t *testing.T) {
+	dict, err := dictionary.Get("en")
+	require.NoError(t, err)
+	g, err := NewOpenGenesis("daily", Rules{MinWordLen: 3, MaxWordLen: 25, MaxAttempts: 6})
+	require.NoError(t, err)
+	h, err := g.Header()
+	require.NoError(t, err)
+	hash, err := h.Hash()
+	require.NoError(t, err)
+
+	day := Day(time.Date(2022, 3, 1, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2022-03-01", day)
+	word := DailyWord(dict, hash, day)
+	assert.Equal(t, word, DailyWord(dict, hash, day))
+	assert.True(t, dict.Contains(word))
+
+	// every node commits to the same word
+	prop, err := DailyProposal(hash, day, word)
+	require.NoError(t, err)
+	again, err := DailyProposal(hash, day, word)
+	require.NoError(t, err)
+	assert.Equal(t, prop, again)
+	assert.True(t, isSolution(word, prop))
+}
+
+func TestDailyResult(t *testing.T) {
+	rules := Rules{MinWordLen: 3, MaxWordLen: 25, MaxAttempts: 3}
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+
+	guesses := []string{"world", "hello"}
+	res, salt, err := NewDailyResult("2022-03-01", "hello", guesses, key)
+	require.NoError(t, err)
+	require.NoError(t, res.Validate(rules))
+	assert.True(t, res.Solved)
+	assert.Equal(t, []string{".y.g.", "ggggg"}, res.Grid)
+	assert.True(t, res.Opens(salt, guesses))
+	assert.False(t, res.Opens(salt, []string{"other", "hello"}))
+
+	// any change breaks the signature
+	tampered := *res
+	tampered.Grid = []string{"ggggg"}
+	assert.Error(t, tampered.Validate(rules))
+
+	// unsolved results have all the attempts
+	res, _, err = NewDailyResult("2022-03-01", "hello", guesses[:1], key)
+	require.NoError(t, err)
+	assert.Error(t, res.Validate(rules))
+	res, _, err = NewDailyResult("2022-03-01", "hello", []string{"world", "world", "world"}, key)
+	require.NoError(t, err)
+	assert.NoError(t, res.Validate(rules))
+	assert.False(t, res.Solved)
+}
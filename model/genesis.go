@@ -0,0 +1,191 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/dictionary"
+)
+
+// Genesis defines the initial state and the rules of a game network.
+type Genesis struct {
+	// ChainID uniquely identifies the network.
+	ChainID string
+	// Word is the commitment to the first word to guess.
+	Word *Word
+	// Rules of the game played in the network.
+	Rules Rules
+}
+
+// Rules define the game parameters all nodes in the network agree on.
+type Rules struct {
+	// MinWordLen and MaxWordLen bound the length of proposed words.
+	MinWordLen, MaxWordLen int
+	// MaxAttempts is the amount of guesses a player has for a word.
+	MaxAttempts int
+	// RoundTimeout is the time after which the proposer of an unsolved word may reveal it,
+	// and after twice of which anyone may skip it. Zero means DefaultRoundTimeout.
+	RoundTimeout time.Duration `json:",omitempty"`
+	// Dictionary names the built-in dictionary words are selected from at random, instead of proposers choosing them.
+	// Empty means proposers choose any words.
+	Dictionary string `json:",omitempty"`
+	// Lanes is the total amount of lanes played in parallel, including the main chain, each with its own current word.
+	// Every lane besides the main chain is a sub-chain. Zero and one mean only the main chain.
+	Lanes int `json:",omitempty"`
+	// HardMode requires every guess to use the hints revealed by the earlier guesses of the player in the round.
+	// Without it, proposers may still require it for their own words.
+	// The check is best-effort: guesses are checked against the earlier ones their player reports, which nodes
+	// compare with the guesses they received, so a player may still leave out guesses it never published.
+	HardMode bool `json:",omitempty"`
+}
+
+// MaxLanes bounds the amount of lanes, as every lane takes its own topic and protocols.
+const MaxLanes = 16
+
+// DefaultRoundTimeout is the round timeout of networks not defining one.
+const DefaultRoundTimeout = time.Hour
+
+// Timeout returns the round timeout defined by the Rules.
+func (r Rules) Timeout() time.Duration {
+	if r.RoundTimeout == 0 {
+		return DefaultRoundTimeout
+	}
+	return r.RoundTimeout
+}
+
+// Difficulty grades words of 'length' letters as "easy", "medium" or "hard", by the third of the length bounds
+// of the Rules they fall into.
+func (r Rules) Difficulty(length int) string {
+	span := r.MaxWordLen - r.MinWordLen + 1
+	switch pos := length - r.MinWordLen; {
+	case pos*3 < span:
+		return "easy"
+	case pos*3 < span*2:
+		return "medium"
+	default:
+		return "hard"
+	}
+}
+
+// NewGenesis creates a new Genesis for the network 'chainID' with the given first 'word' to guess.
+func NewGenesis(chainID, word string, rules Rules) (*Genesis, error) {
+	w, err := NewProposal(word, DefaultSaltLength)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Genesis{
+		ChainID: chainID,
+		Word:    w,
+		Rules:   rules,
+	}
+	return g, g.Validate()
+}
+
+// NewOpenGenesis creates a new Genesis for the network 'chainID', which first word is the chain ID itself.
+// As the word is public anyway, its salts are derived from the chain ID, so that anyone can compute
+// the same Genesis without sharing it.
+func NewOpenGenesis(chainID string, rules Rules) (*Genesis, error) {
+	salts := make([]string, dictionary.Len(chainID))
+	for i := range salts {
+		salts[i] = fmt.Sprintf("%s/%d", chainID, i)
+	}
+
+	chars, err := getChars(chainID, salts)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Genesis{
+		ChainID: chainID,
+		Word:    &Word{Chars: chars},
+		Rules:   rules,
+	}
+	return g, g.Validate()
+}
+
+var chainIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9\-_.]+$`)
+
+// Validate checks whether the Genesis is well-formed.
+func (g *Genesis) Validate() error {
+	if !chainIDRegexp.MatchString(g.ChainID) {
+		return fmt.Errorf("model: invalid chain ID '%s'", g.ChainID)
+	}
+	if g.Rules.MinWordLen <= 0 || g.Rules.MinWordLen > g.Rules.MaxWordLen {
+		return fmt.Errorf("model: invalid word length bounds [%d, %d]", g.Rules.MinWordLen, g.Rules.MaxWordLen)
+	}
+	if g.Rules.MaxAttempts <= 0 {
+		return fmt.Errorf("model: invalid max attempts %d", g.Rules.MaxAttempts)
+	}
+	if g.Rules.RoundTimeout < 0 {
+		return fmt.Errorf("model: invalid round timeout %s", g.Rules.RoundTimeout)
+	}
+	if g.Rules.Lanes < 0 || g.Rules.Lanes > MaxLanes {
+		return fmt.Errorf("model: invalid amount of lanes %d", g.Rules.Lanes)
+	}
+	if g.Rules.Dictionary != "" {
+		dict, err := dictionary.Get(g.Rules.Dictionary)
+		if err != nil {
+			return err
+		}
+		if min, max := dict.Bounds(); min < g.Rules.MinWordLen || max > g.Rules.MaxWordLen {
+			return fmt.Errorf("model: dictionary word lengths [%d, %d] are out of bounds", min, max)
+		}
+	}
+	if g.Word == nil {
+		return errors.New("model: genesis word is missing")
+	}
+	if l := len(g.Word.Chars); l < g.Rules.MinWordLen || l > g.Rules.MaxWordLen {
+		return fmt.Errorf("model: genesis word length %d is out of bounds", l)
+	}
+	return nil
+}
+
+// Lanes returns the Geneses of all the lanes of the network, starting with the main chain itself.
+// Every other lane is a sub-chain with its own chain ID, and so its own topic and protocols, starting from the same
+// first word, so whoever solves it first starts the lane.
+func (g *Genesis) Lanes() []*Genesis {
+	lanes := []*Genesis{g}
+	for i := 1; i < g.Rules.Lanes; i++ {
+		rules := g.Rules
+		rules.Lanes = 0
+		lanes = append(lanes, &Genesis{
+			ChainID: fmt.Sprintf("%s-lane-%d", g.ChainID, i),
+			Word:    g.Word,
+			Rules:   rules,
+		})
+	}
+	return lanes
+}
+
+// Hash computes the hash of the Genesis.
+func (g *Genesis) Hash() (multihash.Multihash, error) {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	return multihash.Encode(hash[:], multihash.SHA2_256)
+}
+
+// Header creates the first Header of the chain, linked to the Genesis.
+func (g *Genesis) Header() (*Header, error) {
+	hash, err := g.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Header{
+		Height:         1,
+		LastHeaderHash: hash,
+		Guess:          &Word{Chars: []*Char{}},
+		Proposal:       g.Word,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenesis(t *testing.T) {
+	require := require.New(t)
+	rules := Rules{MinWordLen: 3, MaxWordLen: 10, MaxAttempts: 5}
+
+	g, err := NewGenesis("office-league", "hello", rules)
+	require.NoError(err)
+
+	h, err := g.Header()
+	require.NoError(err)
+	require.Equal(1, h.Height)
+	require.Equal(g.Word, h.Proposal)
+
+	hash, err := g.Hash()
+	require.NoError(err)
+	require.Equal(hash, h.LastHeaderHash)
+
+	v, err := VerifyString("hello", h.Proposal)
+	require.NoError(err)
+	require.Equal([]bool{true, true, true, true, true}, v)
+
+	_, err = NewGenesis("office league", "hello", rules)
+	require.Error(err)
+
+	_, err = NewGenesis("office-league", "hi", rules)
+	require.Error(err)
+
+	_, err = NewGenesis("office-league", "hello", Rules{MinWordLen: 3, MaxWordLen: 10})
+	require.Error(err)
+
+	tooMany := Rules{MinWordLen: 3, MaxWordLen: 10, MaxAttempts: 5, Lanes: MaxLanes + 1}
+	_, err = NewGenesis("office-league", "hello", tooMany)
+	require.Error(err)
+}
+
+func TestGenesis_Lanes(t *testing.T) {
+	require := require.New(t)
+
+	g, err := NewGenesis("office-league", "hello", Rules{MinWordLen: 3, MaxWordLen: 10, MaxAttempts: 5})
+	require.NoError(err)
+	require.Equal([]*Genesis{g}, g.Lanes())
+
+	// the lanes include the main chain
+	for lanes, expected := range map[int]int{0: 1, 1: 1, 2: 2} {
+		rules := Rules{MinWordLen: 3, MaxWordLen: 10, MaxAttempts: 5, Lanes: lanes}
+		g, err := NewGenesis("office-league", "hello", rules)
+		require.NoError(err)
+		require.Len(g.Lanes(), expected, lanes)
+	}
+
+	g, err = NewGenesis("office-league", "hello", Rules{MinWordLen: 3, MaxWordLen: 10, MaxAttempts: 5, Lanes: 3})
+	require.NoError(err)
+	lanes := g.Lanes()
+	require.Len(lanes, 3)
+	require.Equal(g, lanes[0])
+
+	hashes := make(map[string]bool)
+	for _, lane := range lanes {
+		require.NoError(lane.Validate())
+		require.Equal(g.Word, lane.Word)
+
+		hash, err := lane.Hash()
+		require.NoError(err)
+		hashes[hash.B58String()] = true
+	}
+	require.Len(hashes, 3)
+}
+
+func TestDifficulty(t *testing.T) {
+	rules := Rules{MinWordLen: 3, MaxWordLen: 11}
+	difficulties := map[int]string{3: "easy", 5: "easy", 6: "medium", 8: "medium", 9: "hard", 11: "hard"}
+	for length, difficulty := range difficulties {
+		assert.Equal(t, difficulty, rules.Difficulty(length), "%d letters", length)
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"github.com/p2p-games/wordle/dictionary"
+)
+
+// CheckHardMode checks that the 'guess' of the 'target' word uses the hints revealed by the earlier 'attempts':
+// the letters marked correct stay at their positions, and the letters marked present are used again.
+func CheckHardMode(guess string, attempts []string, target *Word) error {
+	if dictionary.Len(guess) != len(target.Chars) {
+		return reject(RejectWordLength, "guess length %d does not match proposal length %d",
+			dictionary.Len(guess), len(target.Chars))
+	}
+	gw, err := NewGuess(guess, target)
+	if err != nil {
+		return err
+	}
+	return checkHardMode(gw, attempts, target)
+}
+
+// checkHardMode is CheckHardMode for the 'guess' hashed with the salts of the 'target'.
+// The hints of the plaintext attempts are derived from the letter hashes of the target, so the check needs neither
+// the plaintext of the guess nor of the target. It can not tell whether the attempts are all the player made.
+func checkHardMode(guess *Word, attempts []string, target *Word) error {
+	for n, a := range attempts {
+		letters := dictionary.Letters(a)
+		if len(letters) != len(target.Chars) {
+			return reject(RejectWordLength, "attempt %d length %d does not match proposal length %d",
+				n+1, len(letters), len(target.Chars))
+		}
+
+		// marks are ASCII, so they are indexed by letter
+		marks := GradeWord(a, target)
+		need := make(map[string]int)
+		for i, l := range letters {
+			switch marks[i] {
+			case MarkCorrect:
+				if guess.Chars[i].Hash != target.Chars[i].Hash {
+					return reject(RejectHardMode, "letter %d must be '%s' revealed by attempt %d", i+1, l, n+1)
+				}
+				need[l]++
+			case MarkPresent:
+				need[l]++
+			}
+		}
+
+		for _, l := range letters {
+			if need[l] == 0 {
+				continue
+			}
+
+			used := 0
+			for i, ch := range guess.Chars {
+				if ch.Hash == hashChar(l, target.Chars[i].Salt) {
+					used++
+				}
+			}
+			if used < need[l] {
+				return reject(RejectHardMode, "'%s' revealed by attempt %d must be used %d times", l, n+1, need[l])
+			}
+			need[l] = 0
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckHardMode(t *testing.T) {
+	for _, tt := range []struct {
+		target, guess string
+		attempts      []string
+		reason        RejectReason
+	}{
+		{"world", "wrong", nil, 0},
+		// 'w' is correct, 'r' and 'o' are present
+		{"world", "worry", []string{"wrong"}, 0},
+		{"world", "wordy", []string{"wrong"}, 0},
+		{"world", "award", []string{"wrong"}, RejectHardMode},
+		{"world", "wacky", []string{"wrong"}, RejectHardMode},
+		{"world", "robin", []string{"wrong"}, RejectHardMode},
+		// hints of every attempt are needed
+		{"world", "wolds", []string{"wrong", "woods"}, RejectHardMode},
+		{"world", "world", []string{"wrong", "worry"}, 0},
+		// repeated letters are needed as many times as they were marked
+		{"hello", "label", []string{"llama"}, 0},
+		{"hello", "lapse", []string{"llama"}, RejectHardMode},
+		{"world", "word", []string{"wrong"}, RejectWordLength},
+		{"world", "wordy", []string{"wrongs"}, RejectWordLength},
+	} {
+		target, err := NewProposal(tt.target, DefaultSaltLength)
+		require.NoError(t, err)
+
+		err = CheckHardMode(tt.guess, tt.attempts, target)
+		assert.Equal(t, tt.reason, ReasonOf(err), "%s after %v: %v", tt.guess, tt.attempts, err)
+	}
+}
+
+func TestValidateHardMode(t *testing.T) {
+	rules := testRules
+	rules.HardMode = true
+
+	parent, h := newTestChain(t)
+	// the first proposal is 'hello', so 'e' and 'l' must stay
+	h.Attempts = []string{"jelly"}
+	require.NoError(t, h.ValidateBasic(rules))
+	require.NoError(t, h.ValidateNext(parent, rules))
+
+	guess, err := NewHeader(parent, "daily", h.Proposal, "peerID")
+	require.NoError(t, err)
+	guess.Attempts = h.Attempts
+	require.NoError(t, guess.ValidateBasic(rules))
+	assert.Equal(t, RejectHardMode, ReasonOf(guess.ValidateNext(parent, rules)))
+
+	// compliant guesses are still wrong
+	guess, err = NewHeader(parent, "belly", h.Proposal, "peerID")
+	require.NoError(t, err)
+	guess.Attempts = h.Attempts
+	assert.Equal(t, RejectWrongGuess, ReasonOf(guess.ValidateNext(parent, rules)))
+
+	// attempts are only carried in hard rounds and within the limit
+	assert.Equal(t, RejectMalformed, ReasonOf(h.ValidateNext(parent, testRules)))
+	h.Attempts = []string{"jelly", "jelly", "jelly", "jelly", "jelly"}
+	assert.Equal(t, RejectMalformed, ReasonOf(h.ValidateBasic(rules)))
+
+	// proposers may make their own rounds hard
+	h.Attempts = nil
+	h.HardMode = true
+	assert.True(t, h.IsHard(testRules))
+	assert.False(t, parent.IsHard(testRules))
+}
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/dictionary"
 )
 
 // TODO(@Wondertan): Add signatures
@@ -17,8 +20,21 @@ type Header struct {
 
 	Guess    *Word
 	Proposal *Word
+	// Solution is the plaintext of the parent's proposal revealed by the guess. Empty for skips.
+	Solution string `json:",omitempty"`
+	// Selection proves the proposal is selected from the dictionary, if the network has one.
+	Selection *Selection `json:",omitempty"`
+	// Team solving the parent's proposal as a unit, if any. The PeerID is one of its members.
+	Team *Team `json:",omitempty"`
+	// HardMode requires guesses of the proposal to use the hints revealed by earlier guesses.
+	HardMode bool `json:",omitempty"`
+	// Attempts are the earlier guesses of the player in a hard round, in order,
+	// so that anyone can check the guess uses the hints they revealed.
+	Attempts []string `json:",omitempty"`
 
 	PeerID string
+	// Time is the unix time in seconds the Header was created at.
+	Time int64 `json:",omitempty"`
 }
 
 func (h *Header) Hash() (multihash.Multihash, error) {
@@ -45,27 +61,60 @@ type Char struct {
 	Hash string
 }
 
-func NewHeader(last *Header, guess, proposal, peerID string) (*Header, error) {
-	pSalt := make([]string, 0, len(proposal))
-	for i := 0; i < len(proposal); i++ {
-		pSalt = append(pSalt, RandomString(30))
+// CharHashLength is the length of hex encoded hashes of salted letters.
+const CharHashLength = 45
+
+const (
+	// DefaultSaltLength is the default length of salts for proposed words.
+	DefaultSaltLength = 32
+	// MinSaltLength is the minimum length of salts for proposed words, s.t. nobody can precompute
+	// hashes of letters.
+	MinSaltLength = 16
+)
+
+// NewProposal commits to the given 'word' to be guessed by others, salting every letter with a random salt
+// of 'saltLen' length. Letters are Unicode grapheme clusters of the NFC normalized word.
+func NewProposal(word string, saltLen int) (*Word, error) {
+	if saltLen < MinSaltLength {
+		return nil, fmt.Errorf("model: salt length %d is less than minimum %d", saltLen, MinSaltLength)
 	}
-	pw, err := GetChars(proposal, pSalt)
+
+	n := dictionary.Len(word)
+	salts := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		salts = append(salts, RandomString(saltLen))
+	}
+	chars, err := getChars(word, salts)
 	if err != nil {
 		return nil, err
 	}
 
-	gSalts := make([]string, len(last.Proposal.Chars))
-	for i, ch := range last.Proposal.Chars {
-		gSalts[i] = ch.Salt
+	return &Word{Chars: chars}, nil
+}
+
+// NewGuess hashes the guessed 'word' with salts of the 'challenge' it guesses.
+func NewGuess(word string, challenge *Word) (*Word, error) {
+	salts := make([]string, len(challenge.Chars))
+	for i, ch := range challenge.Chars {
+		salts[i] = ch.Salt
+	}
+
+	chars, err := getChars(word, salts)
+	if err != nil {
+		return nil, err
 	}
 
+	return &Word{Chars: chars}, nil
+}
+
+// NewHeader creates a Header on top of the 'last' one with the 'guess' of the last proposal and a new 'proposal'.
+func NewHeader(last *Header, guess string, proposal *Word, peerID string) (*Header, error) {
 	hash, err := last.Hash()
 	if err != nil {
 		return nil, err
 	}
 
-	gw, err := GetChars(guess, gSalts)
+	gw, err := NewGuess(guess, last.Proposal)
 	if err != nil {
 		return nil, err
 	}
@@ -74,41 +123,89 @@ func NewHeader(last *Header, guess, proposal, peerID string) (*Header, error) {
 		Height:         last.Height + 1,
 		LastHeaderHash: hash,
 		PeerID:         peerID,
-		Guess: &Word{
-			Chars: gw,
-		},
-		Proposal: &Word{
-			Chars: pw,
-		},
+		Guess:          gw,
+		Proposal:       proposal,
+		Solution:       dictionary.Normalize(guess),
+		Time:           nextTime(last),
 	}, nil
 }
 
+// NewSkip creates a Header on top of the 'last' one, which skips the last proposal nobody solved or revealed
+// and starts a new round with the 'proposal'.
+func NewSkip(last *Header, proposal *Word, peerID string) (*Header, error) {
+	hash, err := last.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Header{
+		Height:         last.Height + 1,
+		LastHeaderHash: hash,
+		PeerID:         peerID,
+		Guess:          &Word{Chars: []*Char{}},
+		Proposal:       proposal,
+		Time:           nextTime(last),
+	}, nil
+}
+
+// IsSkip reports whether the Header skips the proposal of its parent instead of solving it.
+func (h *Header) IsSkip() bool {
+	return h.Guess != nil && len(h.Guess.Chars) == 0
+}
+
+// IsHard reports whether the round of the Header's proposal is played in hard mode under the 'rules'.
+func (h *Header) IsHard(rules Rules) bool {
+	return rules.HardMode || h.HardMode
+}
+
+// RevealAfter is the time after which the proposer of the Header may reveal its proposal
+// and start a new round with another one.
+func (h *Header) RevealAfter(rules Rules) time.Time {
+	return time.Unix(h.Time, 0).Add(rules.Timeout())
+}
+
+// SkipAfter is the time after which anyone may skip the proposal of the Header,
+// if the proposer did not reveal it.
+func (h *Header) SkipAfter(rules Rules) time.Time {
+	return time.Unix(h.Time, 0).Add(2 * rules.Timeout())
+}
+
+// nextTime returns the current time for a Header following the 'last', which is never before the last one.
+func nextTime(last *Header) int64 {
+	now := time.Now().Unix()
+	if now < last.Time {
+		return last.Time
+	}
+	return now
+}
+
 func VerifyString(guess string, challenge *Word) ([]bool, error) {
 	result := make([]bool, len(challenge.Chars))
-	if len(guess) != len(challenge.Chars) {
+	if dictionary.Len(guess) != len(challenge.Chars) {
 		return result, nil
 	}
 
-	salts := make([]string, 0, len(challenge.Chars))
-	for _, ch := range challenge.Chars {
-		salts = append(salts, ch.Salt)
-	}
-
-	gch, err := GetChars(guess, salts)
+	gw, err := NewGuess(guess, challenge)
 	if err != nil {
 		return result, err
 	}
 
 	for i, ch := range challenge.Chars {
-		result[i] = gch[i].Hash == ch.Hash
+		result[i] = gw.Chars[i].Hash == ch.Hash
 	}
 
 	return result, nil
 }
 
+// Verify reports whether the 'guess' solves the 'challenge'.
+// It never panics on malformed words, reporting them as wrong guesses.
 func Verify(guess, challenge *Word) bool {
+	if guess == nil || challenge == nil || len(guess.Chars) != len(challenge.Chars) {
+		return false
+	}
+
 	for i, ch := range challenge.Chars {
-		if guess.Chars[i].Hash != ch.Hash {
+		if guess.Chars[i] == nil || ch == nil || guess.Chars[i].Hash != ch.Hash {
 			return false
 		}
 	}
@@ -117,27 +214,28 @@ func Verify(guess, challenge *Word) bool {
 
 var ErrSaltsAndCharsDidntMatch = errors.New("number of salts and number of letters didn't match")
 
-func GetChars(word string, salts []string) ([]*Char, error) {
-	if len(word) != len(salts) {
-		return nil, ErrSaltsAndCharsDidntMatch
-	}
-	chars := make([]*Char, 0, len(word))
+// hashChar hashes the 'letter' salted with the 'salt'.
+func hashChar(letter, salt string) string {
 	h := sha256.New()
-	for i, r := range word {
-		salt := salts[i]
-		h.Reset()
-		h.Write([]byte{byte(r)})
-		h.Write([]byte(salt))
+	h.Write([]byte(letter))
+	h.Write([]byte(salt))
+	return fmt.Sprintf("%x", h.Sum(nil))[:CharHashLength]
+}
 
-		hash := fmt.Sprintf("%x", h.Sum(nil))[:45]
+// getChars salts and hashes every letter of the 'word'.
+// It is kept private, so that words can't be committed with caller-supplied salts.
+func getChars(word string, salts []string) ([]*Char, error) {
+	letters := dictionary.Letters(word)
+	if len(letters) != len(salts) {
+		return nil, ErrSaltsAndCharsDidntMatch
+	}
 
-		ch := &Char{
-			Salt: salt,
-			Hash: hash,
+	chars := make([]*Char, len(letters))
+	for i, l := range letters {
+		chars[i] = &Char{
+			Salt: salts[i],
+			Hash: hashChar(l, salts[i]),
 		}
-
-		chars = append(chars, ch)
 	}
-
 	return chars, nil
 }
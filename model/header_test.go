@@ -17,7 +17,7 @@ func TestNewHeader(t *testing.T) {
 			{Salt: "d"},
 			{Salt: "e"},
 		},
-	}}, "hello", "proposal", "peerID")
+	}}, "hello", &Word{}, "peerID")
 	require.NoError(err)
 
 	require.Equal(&Word{
@@ -49,7 +49,7 @@ func TestNewHeader(t *testing.T) {
 func TestVerify(t *testing.T) {
 	require := require.New(t)
 
-	ch, err := GetChars("table", []string{"a", "b", "c", "d", "e"})
+	ch, err := getChars("table", []string{"a", "b", "c", "d", "e"})
 	require.NoError(err)
 
 	v, err := VerifyString("apple", &Word{Chars: ch})
@@ -61,7 +61,7 @@ func TestVerify(t *testing.T) {
 func TestVerifyDifferentLengths(t *testing.T) {
 	require := require.New(t)
 
-	ch, err := GetChars("table", []string{"a", "b", "c", "d", "e"})
+	ch, err := getChars("table", []string{"a", "b", "c", "d", "e"})
 	require.NoError(err)
 
 	v, err := VerifyString("shortable", &Word{Chars: ch})
@@ -69,7 +69,7 @@ func TestVerifyDifferentLengths(t *testing.T) {
 
 	require.Equal([]bool{false, false, false, false, false}, v)
 
-	ch, err = GetChars("shortable", []string{"a", "b", "c", "d", "e", "f", "g", "h", "j"})
+	ch, err = getChars("shortable", []string{"a", "b", "c", "d", "e", "f", "g", "h", "j"})
 	require.NoError(err)
 
 	v, err = VerifyString("short", &Word{Chars: ch})
@@ -77,3 +77,53 @@ func TestVerifyDifferentLengths(t *testing.T) {
 
 	require.Equal([]bool{false, false, false, false, false, false, false, false, false}, v)
 }
+
+func TestNewProposal(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewProposal("hello", MinSaltLength-1)
+	require.Error(err)
+
+	a, err := NewProposal("hello", DefaultSaltLength)
+	require.NoError(err)
+	b, err := NewProposal("hello", DefaultSaltLength)
+	require.NoError(err)
+
+	for i := range a.Chars {
+		require.Len(a.Chars[i].Salt, DefaultSaltLength)
+		require.NotEqual(a.Chars[i].Salt, b.Chars[i].Salt)
+		require.NotEqual(a.Chars[i].Hash, b.Chars[i].Hash)
+	}
+
+	v, err := VerifyString("hello", a)
+	require.NoError(err)
+	require.Equal([]bool{true, true, true, true, true}, v)
+}
+
+func TestVerifyUnicode(t *testing.T) {
+	tests := []struct {
+		name, word, guess string
+		letters           int
+		result            []bool
+	}{
+		{"spanish", "cañón", "canon", 5, []bool{true, true, false, false, true}},
+		{"spanish decomposed", "cañón", "can\u0303o\u0301n", 5, []bool{true, true, true, true, true}},
+		{"german", "größe", "grüße", 5, []bool{true, true, false, true, true}},
+		{"cyrillic", "щётка", "щетка", 5, []bool{true, false, true, true, true}},
+		{"cyrillic length", "книга", "книгаа", 5, []bool{false, false, false, false, false}},
+		// no precomposed form exists, so the letter is a grapheme of two runes
+		{"combining", "x\u0301yz", "x\u0301yy", 3, []bool{true, true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop, err := NewProposal(tt.word, DefaultSaltLength)
+			require.NoError(t, err)
+			require.Len(t, prop.Chars, tt.letters)
+
+			v, err := VerifyString(tt.guess, prop)
+			require.NoError(t, err)
+			require.Equal(t, tt.result, v)
+		})
+	}
+}
@@ -1,16 +1,23 @@
 package model
 
 import (
-	"math/rand"
+	"crypto/rand"
+	"math/big"
 )
 
 var characterRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
 
-// RandomString generates a random string of n length
+// RandomString generates a cryptographically secure random string of n length
 func RandomString(n int) string {
+	max := big.NewInt(int64(len(characterRunes)))
 	b := make([]rune, n)
 	for i := range b {
-		b[i] = characterRunes[rand.Intn(len(characterRunes))]
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			// crypto/rand does not fail on supported platforms
+			panic(err)
+		}
+		b[i] = characterRunes[idx.Int64()]
 	}
 	return string(b)
 }
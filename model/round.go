@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Round is a finished round of the game: the word proposed in one Header and solved, revealed or skipped in the next.
+type Round struct {
+	// Height of the Header proposing the word.
+	Height int
+	// Word is the plaintext of the proposed word. Empty if skipped.
+	Word string
+	// Proposer and Solver are the peers who proposed the word and ended the round.
+	Proposer, Solver string
+	// Team are the PeerIDs of the members of the team the Solver solved the word with, if any.
+	Team []string
+	// Revealed is true if the proposer revealed the word itself after the round timeout.
+	Revealed bool
+	// Skipped is true if nobody solved or revealed the word.
+	Skipped bool
+	// Start and End are the times the word was proposed and the round ended.
+	Start, End time.Time
+}
+
+// NewRound describes the round of the word proposed by the 'proposal' Header, which the 'next' Header ended.
+// It expects the 'next' Header to be valid on top of the 'proposal' one.
+func NewRound(proposal, next *Header) *Round {
+	r := &Round{
+		Height:   proposal.Height,
+		Word:     next.Solution,
+		Proposer: proposal.PeerID,
+		Solver:   next.PeerID,
+		Revealed: !next.IsSkip() && next.PeerID == proposal.PeerID,
+		Skipped:  next.IsSkip(),
+		Start:    time.Unix(proposal.Time, 0),
+		End:      time.Unix(next.Time, 0),
+	}
+	if next.Team != nil {
+		r.Team = next.Team.Peers()
+	}
+	return r
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/p2p-games/wordle/dictionary"
+)
+
+// selectionPrefix separates selection signatures from any other signatures of the same key.
+const selectionPrefix = "wordle/selection/"
+
+// Selection proves that the proposed word is selected at random from the dictionary of the network.
+// The randomness comes from the signature of the proposer over the parent hash, so that neither the proposer
+// can choose the word, nor anyone else can predict it before the parent exists.
+//
+// TODO(@Wondertan): Signatures of some key types, e.g. Ed25519, are deterministic only by convention, so a proposer
+//
+//	with a custom signer can still grind for a word. Replace with a proper VRF, once we have an implementation.
+type Selection struct {
+	// Len is the length in letters of the words the proposer chose to select from.
+	// Zero means all the words of the dictionary.
+	Len int `json:",omitempty"`
+	// Index of the proposed word in the dictionary, or among its words of the Len.
+	Index int
+	// Proof is the signature of the proposer over the parent hash.
+	Proof []byte
+	// PubKey is the marshalled public key of the proposer, matching its PeerID.
+	PubKey []byte
+}
+
+// Select picks the word for the Header following the 'parent' from the dictionary 'dict' with the proposer's 'key'.
+// The word is picked among the words of 'length' letters, or among all the words, if the length is zero.
+func Select(dict *dictionary.Dictionary, parent *Header, key crypto.PrivKey, length int) (string, *Selection, error) {
+	words := dict.Words(length)
+	if len(words) == 0 {
+		return "", nil, fmt.Errorf("model: no %d letter words in the '%s' dictionary", length, dict.Name())
+	}
+
+	msg, err := selectionMsg(parent)
+	if err != nil {
+		return "", nil, err
+	}
+
+	proof, err := key.Sign(msg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	pub, err := crypto.MarshalPublicKey(key.GetPublic())
+	if err != nil {
+		return "", nil, err
+	}
+
+	idx := selectionIndex(proof, len(words))
+	return words[idx], &Selection{Len: length, Index: idx, Proof: proof, PubKey: pub}, nil
+}
+
+// validateSelection checks that the Header proposes the word selected from the dictionary of the 'rules'.
+func (h *Header) validateSelection(parent *Header, rules Rules) error {
+	if rules.Dictionary == "" {
+		if h.Selection != nil {
+			return reject(RejectSelection, "selection without a dictionary")
+		}
+		return nil
+	}
+	if h.Selection == nil {
+		return reject(RejectSelection, "selection is missing")
+	}
+
+	if l := h.Selection.Len; l != 0 && (l < rules.MinWordLen || l > rules.MaxWordLen) {
+		return reject(RejectWordLength, "selection length %d is out of bounds [%d, %d]",
+			l, rules.MinWordLen, rules.MaxWordLen)
+	}
+	dict, err := dictionary.Get(rules.Dictionary)
+	if err != nil {
+		return err
+	}
+	words := dict.Words(h.Selection.Len)
+	if len(words) == 0 {
+		return reject(RejectSelection, "no %d letter words in the dictionary", h.Selection.Len)
+	}
+
+	pub, err := crypto.UnmarshalPublicKey(h.Selection.PubKey)
+	if err != nil {
+		return reject(RejectSelection, "public key: %s", err)
+	}
+	id, err := peer.IDFromPublicKey(pub)
+	if err != nil || id.String() != h.PeerID {
+		return reject(RejectSelection, "public key does not match peer %s", h.PeerID)
+	}
+
+	msg, err := selectionMsg(parent)
+	if err != nil {
+		return err
+	}
+	ok, err := pub.Verify(msg, h.Selection.Proof)
+	if err != nil || !ok {
+		return reject(RejectSelection, "invalid proof")
+	}
+
+	idx := selectionIndex(h.Selection.Proof, len(words))
+	if h.Selection.Index != idx {
+		return reject(RejectSelection, "index %d is not the selected %d", h.Selection.Index, idx)
+	}
+	if !isSolution(words[idx], h.Proposal) {
+		return reject(RejectSelection, "proposal is not the selected word")
+	}
+	return nil
+}
+
+func selectionMsg(parent *Header) ([]byte, error) {
+	hash, err := parent.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(selectionPrefix), hash...), nil
+}
+
+// selectionIndex derives the index of a word in a dictionary of size 'n' from the 'proof'.
+func selectionIndex(proof []byte, n int) int {
+	out := sha256.Sum256(proof)
+	return int(binary.BigEndian.Uint64(out[:8]) % uint64(n))
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/p2p-games/wordle/dictionary"
+)
+
+func TestSelection(t *testing.T) {
+	rules := testRules
+	rules.Dictionary = "en"
+	dict, err := dictionary.Get(rules.Dictionary)
+	require.NoError(t, err)
+
+	_, parent := newTestChain(t)
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	id, err := peer.IDFromPrivateKey(key)
+	require.NoError(t, err)
+
+	newHeader := func(t *testing.T, length int) *Header {
+		word, sel, err := Select(dict, parent, key, length)
+		require.NoError(t, err)
+		assert.True(t, dict.Contains(word))
+		if length != 0 {
+			assert.Equal(t, length, dictionary.Len(word))
+		}
+
+		prop, err := NewProposal(word, DefaultSaltLength)
+		require.NoError(t, err)
+		h, err := NewHeader(parent, "world", prop, id.String())
+		require.NoError(t, err)
+		h.Selection = sel
+		return h
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(h *Header)
+		reason RejectReason
+	}{
+		{"valid", func(h *Header) {}, 0},
+		{"other length", func(h *Header) { h.Selection.Len = 6 }, RejectSelection},
+		{"no words of length", func(h *Header) { h.Selection.Len = 8 }, RejectSelection},
+		{"length out of bounds", func(h *Header) { h.Selection.Len = 2 }, RejectWordLength},
+		{"missing", func(h *Header) { h.Selection = nil }, RejectSelection},
+		{"other index", func(h *Header) { h.Selection.Index = (h.Selection.Index + 1) % dict.Len() }, RejectSelection},
+		{"other peer", func(h *Header) { h.PeerID = "other" }, RejectSelection},
+		{"bad proof", func(h *Header) { h.Selection.Proof[0]++ }, RejectSelection},
+		{"chosen word", func(h *Header) {
+			var err error
+			h.Proposal, err = NewProposal("qwxyz", DefaultSaltLength)
+			require.NoError(t, err)
+		}, RejectSelection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHeader(t, 5)
+			tt.mutate(h)
+
+			err := h.ValidateBasic(rules)
+			require.NoError(t, err)
+			err = h.ValidateNext(parent, rules)
+			require.Equal(t, tt.reason, ReasonOf(err), err)
+		})
+	}
+
+	// proposers choose any length the dictionary has
+	for _, length := range append(dict.Lens(), 0) {
+		h := newHeader(t, length)
+		assert.NoError(t, h.ValidateNext(parent, rules), length)
+	}
+	_, _, err = Select(dict, parent, key, 8)
+	assert.Error(t, err)
+
+	// without a dictionary proposers choose words
+	h := newHeader(t, 5)
+	err = h.ValidateNext(parent, testRules)
+	assert.Equal(t, RejectSelection, ReasonOf(err))
+}
@@ -0,0 +1,167 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/p2p-games/wordle/dictionary"
+)
+
+// teamPrefix separates membership signatures from any other signatures of the same key.
+const teamPrefix = "wordle/team/"
+
+// MaxTeamSize bounds the amount of members of a Team recorded in a Header.
+const MaxTeamSize = 16
+
+// maxTeamNameLength bounds the length of Team names.
+const maxTeamNameLength = 64
+
+// Team is a group of players solving words as a unit, sharing their guesses and attempts.
+// Every member signs its membership, so that the solver recording the Team can't credit anyone else.
+type Team struct {
+	// Name of the team.
+	Name string
+	// Members are the signed memberships of the players in the team.
+	Members []*Membership
+}
+
+// Membership is the statement of a player being a member of a Team, signed by the player.
+// It is signed for the team name only, so any member solving a word may record it.
+type Membership struct {
+	// PeerID of the member.
+	PeerID string
+	// PubKey is the marshalled public key of the member, matching the PeerID.
+	PubKey []byte
+	// Signature of the member over the team name.
+	Signature []byte
+}
+
+// NewMembership signs the membership of the player with the 'key' in the team 'name'.
+func NewMembership(name string, key crypto.PrivKey) (*Membership, error) {
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := crypto.MarshalPublicKey(key.GetPublic())
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(membershipMsg(name))
+	if err != nil {
+		return nil, err
+	}
+	return &Membership{PeerID: id.String(), PubKey: pub, Signature: sig}, nil
+}
+
+// Verify checks that the Membership is signed by its player for the team 'name'.
+func (m *Membership) Verify(name string) error {
+	return verifySignature(m.PubKey, m.PeerID, membershipMsg(name), m.Signature)
+}
+
+// Peers returns the PeerIDs of the members of the Team.
+func (t *Team) Peers() []string {
+	peers := make([]string, len(t.Members))
+	for i, m := range t.Members {
+		peers[i] = m.PeerID
+	}
+	return peers
+}
+
+func membershipMsg(name string) []byte {
+	return append([]byte(teamPrefix), name...)
+}
+
+// ValidateTeamName checks whether the 'name' can name a Team.
+func ValidateTeamName(name string) error {
+	if len(name) > maxTeamNameLength || !chainIDRegexp.MatchString(name) {
+		return reject(RejectMalformed, "invalid team name '%s'", name)
+	}
+	return nil
+}
+
+// validate checks that the Team is well-formed, signed by its members and has the 'solver' among them.
+func (t *Team) validate(solver string) error {
+	if err := ValidateTeamName(t.Name); err != nil {
+		return err
+	}
+	if len(t.Members) == 0 || len(t.Members) > MaxTeamSize {
+		return reject(RejectMalformed, "%d team members out of [1, %d]", len(t.Members), MaxTeamSize)
+	}
+
+	solverIn := false
+	seen := make(map[string]bool, len(t.Members))
+	for _, m := range t.Members {
+		if m == nil {
+			return reject(RejectMalformed, "team member is missing")
+		}
+		if seen[m.PeerID] {
+			return reject(RejectMalformed, "duplicate team member %s", m.PeerID)
+		}
+		if len(m.PubKey) > maxSelectionSize || len(m.Signature) > maxSelectionSize {
+			return reject(RejectMalformed, "membership of %s is too big", m.PeerID)
+		}
+		if err := m.Verify(t.Name); err != nil {
+			return reject(RejectMalformed, "membership of %s: %s", m.PeerID, err)
+		}
+		seen[m.PeerID] = true
+		solverIn = solverIn || m.PeerID == solver
+	}
+	if !solverIn {
+		return reject(RejectMalformed, "solver %s is not in the team", solver)
+	}
+	return nil
+}
+
+// Standing is the amount of rounds a player solved and its score for them.
+type Standing struct {
+	PeerID string
+	Solved int
+	// Score weighs every solved word by its length in letters, as longer words are harder to guess.
+	Score int
+}
+
+// Leaderboard ranks the players by their score for the 'rounds' they solved, from the best to the worst.
+// Rounds solved by a team credit every member of the team.
+// Ties are ordered by the amount of solved rounds and then by PeerID.
+func Leaderboard(rounds []*Round) []Standing {
+	standings := make(map[string]*Standing)
+	credit := func(id string, r *Round) {
+		st, ok := standings[id]
+		if !ok {
+			st = &Standing{PeerID: id}
+			standings[id] = st
+		}
+		st.Solved++
+		st.Score += dictionary.Len(r.Word)
+	}
+	for _, r := range rounds {
+		if r.Skipped || r.Revealed {
+			continue
+		}
+		if len(r.Team) == 0 {
+			credit(r.Solver, r)
+			continue
+		}
+		for _, m := range r.Team {
+			credit(m, r)
+		}
+	}
+
+	board := make([]Standing, 0, len(standings))
+	for _, st := range standings {
+		board = append(board, *st)
+	}
+	sort.Slice(board, func(i, j int) bool {
+		if board[i].Score != board[j].Score {
+			return board[i].Score > board[j].Score
+		}
+		if board[i].Solved != board[j].Solved {
+			return board[i].Solved > board[j].Solved
+		}
+		return board[i].PeerID < board[j].PeerID
+	})
+	return board
+}
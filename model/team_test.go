@@ -0,0 +1,82 @@
+package model
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeam(t *testing.T) {
+	membership := func(name string) *Membership {
+		key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(t, err)
+		m, err := NewMembership(name, key)
+		require.NoError(t, err)
+		require.NoError(t, m.Verify(name))
+		return m
+	}
+	solver, mate, stranger := membership("owls"), membership("owls"), membership("larks")
+	// credits a player who did not sign the membership
+	forged := &Membership{PeerID: stranger.PeerID, PubKey: stranger.PubKey, Signature: mate.Signature}
+
+	tests := []struct {
+		name   string
+		team   *Team
+		reason RejectReason
+	}{
+		{"solo", &Team{Name: "owls", Members: []*Membership{solver}}, 0},
+		{"team", &Team{Name: "owls", Members: []*Membership{solver, mate}}, 0},
+		{"bad name", &Team{Name: "night owls", Members: []*Membership{solver}}, RejectMalformed},
+		{"no members", &Team{Name: "owls"}, RejectMalformed},
+		{"nil member", &Team{Name: "owls", Members: []*Membership{solver, nil}}, RejectMalformed},
+		{"duplicate member", &Team{Name: "owls", Members: []*Membership{solver, solver}}, RejectMalformed},
+		{"solver not in team", &Team{Name: "owls", Members: []*Membership{mate}}, RejectMalformed},
+		{"member of another team", &Team{Name: "owls", Members: []*Membership{solver, stranger}}, RejectMalformed},
+		{"forged membership", &Team{Name: "owls", Members: []*Membership{solver, forged}}, RejectMalformed},
+		{"too many members", &Team{Name: "owls", Members: make([]*Membership, MaxTeamSize+1)}, RejectMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent, h := newTestChain(t)
+			h.PeerID = solver.PeerID
+			h.Team = tt.team
+
+			err := h.ValidateBasic(testRules)
+			if err == nil {
+				err = h.ValidateNext(parent, testRules)
+			}
+			require.Equal(t, tt.reason, ReasonOf(err), err)
+		})
+	}
+
+	// teams only solve words
+	parent, _ := newTestChain(t)
+	skip, err := NewSkip(parent, parent.Proposal, solver.PeerID)
+	require.NoError(t, err)
+	skip.Team = &Team{Name: "owls", Members: []*Membership{solver}}
+	assert.Equal(t, RejectMalformed, ReasonOf(skip.ValidateBasic(testRules)))
+}
+
+func TestLeaderboard(t *testing.T) {
+	rounds := []*Round{
+		{Height: 1, Word: "hello", Solver: "alice"},
+		{Height: 2, Word: "world", Solver: "bob", Team: []string{"bob", "carol"}},
+		{Height: 3, Word: "moon", Solver: "carol", Team: []string{"carol", "bob"}},
+		{Height: 4, Word: "reveal", Solver: "dave", Revealed: true},
+		{Height: 5, Solver: "dave", Skipped: true},
+		{Height: 6, Word: "sun", Solver: "alice"},
+		{Height: 7, Word: "strength", Solver: "erin"},
+	}
+
+	// teams credit every member for the words, which are weighed by their length
+	assert.Equal(t, []Standing{
+		{PeerID: "bob", Solved: 2, Score: 9},
+		{PeerID: "carol", Solved: 2, Score: 9},
+		{PeerID: "alice", Solved: 2, Score: 8},
+		{PeerID: "erin", Solved: 1, Score: 8},
+	}, Leaderboard(rounds))
+}
@@ -0,0 +1,294 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/dictionary"
+)
+
+// MaxSaltLength bounds the length of salts, s.t. nobody can bloat headers with them.
+const MaxSaltLength = 256
+
+// MaxHeight bounds the height of Headers far above any chain that could ever be played,
+// s.t. nobody can make others sync towards made up heights.
+const MaxHeight = 1 << 32
+
+// maxSelectionSize bounds the size of selection proofs and keys, fitting RSA keys of up to 4096 bits.
+const maxSelectionSize = 1024
+
+// RejectReason describes why a Header was rejected.
+type RejectReason uint8
+
+const (
+	// RejectMalformed means required fields of the Header are missing.
+	RejectMalformed RejectReason = iota + 1
+	// RejectWordLength means a word is too short, too long or does not match the length of the guessed one.
+	RejectWordLength
+	// RejectSalt means a salt is of a wrong format or does not match the salt of the guessed letter.
+	RejectSalt
+	// RejectHash means a letter hash or the parent hash is of a wrong format.
+	RejectHash
+	// RejectHeight means the Header does not follow the parent's height.
+	RejectHeight
+	// RejectParent means the Header does not link to the parent's hash.
+	RejectParent
+	// RejectWrongGuess means the Header's guess does not solve the parent's proposal.
+	RejectWrongGuess
+	// RejectTime means the Header is older than its parent, or reveals or skips the parent's proposal
+	// before the round timeout.
+	RejectTime
+	// RejectSolution means the revealed plaintext does not match the parent's proposal.
+	RejectSolution
+	// RejectSelection means the proposal is not the word selected from the dictionary.
+	RejectSelection
+	// RejectHardMode means the guess ignores hints revealed by the player's earlier guesses in a hard round.
+	RejectHardMode
+)
+
+var rejectReasonString = map[RejectReason]string{
+	RejectMalformed:  "malformed",
+	RejectWordLength: "word length",
+	RejectSalt:       "salt",
+	RejectHash:       "hash",
+	RejectHeight:     "height",
+	RejectParent:     "parent",
+	RejectWrongGuess: "wrong guess",
+	RejectTime:       "time",
+	RejectSolution:   "solution",
+	RejectSelection:  "selection",
+	RejectHardMode:   "hard mode",
+}
+
+// String converts RejectReason to its string representation.
+func (r RejectReason) String() string {
+	s, ok := rejectReasonString[r]
+	if !ok {
+		return "unknown"
+	}
+	return s
+}
+
+// ValidationError is returned for invalid Headers.
+type ValidationError struct {
+	Reason RejectReason
+	Msg    string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("model: invalid header (%s): %s", e.Reason, e.Msg)
+}
+
+func reject(reason RejectReason, format string, args ...interface{}) error {
+	return &ValidationError{Reason: reason, Msg: fmt.Sprintf(format, args...)}
+}
+
+// ReasonOf extracts the RejectReason out of the error returned from validation.
+// Zero is returned if the error is not a ValidationError.
+func ReasonOf(err error) RejectReason {
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		return 0
+	}
+	return verr.Reason
+}
+
+// ValidateBasic checks whether the Header is well-formed under the given 'rules' without its parent.
+func (h *Header) ValidateBasic(rules Rules) error {
+	if h.Height < 2 {
+		// 1 is for genesis, which is never validated
+		return reject(RejectHeight, "height %d is too low", h.Height)
+	}
+	if h.Height > MaxHeight {
+		return reject(RejectHeight, "height %d is too high", h.Height)
+	}
+
+	if h.Time < 0 {
+		return reject(RejectTime, "negative time %d", h.Time)
+	}
+
+	if _, err := multihash.Decode(h.LastHeaderHash); err != nil {
+		return reject(RejectHash, "parent hash: %s", err)
+	}
+
+	if h.Guess == nil || h.Proposal == nil {
+		return reject(RejectMalformed, "guess or proposal is missing")
+	}
+
+	if l := len(h.Proposal.Chars); l < rules.MinWordLen || l > rules.MaxWordLen {
+		return reject(RejectWordLength, "proposal length %d is out of bounds [%d, %d]",
+			l, rules.MinWordLen, rules.MaxWordLen)
+	}
+	if l := len(h.Guess.Chars); l > rules.MaxWordLen {
+		return reject(RejectWordLength, "guess length %d is out of bounds", l)
+	}
+	if l := dictionary.Len(h.Solution); l > rules.MaxWordLen {
+		return reject(RejectWordLength, "solution length %d is out of bounds", l)
+	}
+	if h.Solution != dictionary.Normalize(h.Solution) {
+		return reject(RejectSolution, "solution is not NFC normalized")
+	}
+	if h.IsSkip() && h.Solution != "" {
+		return reject(RejectMalformed, "skip reveals a solution")
+	}
+	if len(h.Attempts) > 0 {
+		if h.IsSkip() {
+			return reject(RejectMalformed, "skip with attempts")
+		}
+		if len(h.Attempts) >= rules.MaxAttempts {
+			return reject(RejectMalformed, "%d attempts before the guess exceed the limit %d",
+				len(h.Attempts), rules.MaxAttempts)
+		}
+		for _, a := range h.Attempts {
+			if dictionary.Len(a) > rules.MaxWordLen {
+				return reject(RejectWordLength, "attempt length %d is out of bounds", dictionary.Len(a))
+			}
+			if a != dictionary.Normalize(a) {
+				return reject(RejectMalformed, "attempt is not NFC normalized")
+			}
+		}
+	}
+	if h.Team != nil {
+		if h.IsSkip() {
+			return reject(RejectMalformed, "skip by a team")
+		}
+		if err := h.Team.validate(h.PeerID); err != nil {
+			return err
+		}
+	}
+	if sel := h.Selection; sel != nil && (len(sel.Proof) > maxSelectionSize || len(sel.PubKey) > maxSelectionSize) {
+		return reject(RejectMalformed, "selection is too big")
+	}
+
+	for _, ch := range h.Proposal.Chars {
+		if err := validateChar(ch, MinSaltLength); err != nil {
+			return err
+		}
+	}
+	for _, ch := range h.Guess.Chars {
+		// guesses use salts of the guessed proposal, which are verified against it
+		if err := validateChar(ch, 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateNext checks whether the Header correctly extends its 'parent' under the given 'rules'.
+// Its proposal must be the word selected from the dictionary, if the rules define one.
+// The Header either solves the parent's proposal, where the proposer itself may only reveal it
+// after the round timeout, or skips it, if nobody revealed it for twice the round timeout.
+// In hard rounds, the guess must use the hints revealed by the attempts it carries.
+// It expects the Header to be valid per ValidateBasic.
+func (h *Header) ValidateNext(parent *Header, rules Rules) error {
+	if h.Height != parent.Height+1 {
+		return reject(RejectHeight, "height %d does not follow parent height %d", h.Height, parent.Height)
+	}
+
+	hash, err := parent.Hash()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(hash, h.LastHeaderHash) {
+		return reject(RejectParent, "parent hash mismatch")
+	}
+
+	if h.Time < parent.Time {
+		return reject(RejectTime, "time %d is before parent time %d", h.Time, parent.Time)
+	}
+	if err := h.validateSelection(parent, rules); err != nil {
+		return err
+	}
+	if h.IsSkip() {
+		if time.Unix(h.Time, 0).Before(parent.SkipAfter(rules)) {
+			return reject(RejectTime, "skip before %s", parent.SkipAfter(rules))
+		}
+		return nil
+	}
+
+	if len(h.Guess.Chars) != len(parent.Proposal.Chars) {
+		return reject(RejectWordLength, "guess length %d does not match proposal length %d",
+			len(h.Guess.Chars), len(parent.Proposal.Chars))
+	}
+	for i, ch := range parent.Proposal.Chars {
+		if h.Guess.Chars[i].Salt != ch.Salt {
+			return reject(RejectSalt, "guess salt %d does not match proposal salt", i)
+		}
+	}
+
+	if parent.IsHard(rules) {
+		if err := checkHardMode(h.Guess, h.Attempts, parent.Proposal); err != nil {
+			return err
+		}
+	} else if len(h.Attempts) > 0 {
+		return reject(RejectMalformed, "attempts outside of hard mode")
+	}
+
+	if !Verify(h.Guess, parent.Proposal) {
+		return reject(RejectWrongGuess, "guess does not solve the proposal")
+	}
+	if !isSolution(h.Solution, parent.Proposal) {
+		return reject(RejectSolution, "solution does not match the proposal")
+	}
+
+	if h.PeerID == parent.PeerID && time.Unix(h.Time, 0).Before(parent.RevealAfter(rules)) {
+		return reject(RejectTime, "proposer reveals before %s", parent.RevealAfter(rules))
+	}
+
+	return nil
+}
+
+// isSolution reports whether the plaintext 'word' is committed to in the 'proposal'.
+func isSolution(word string, proposal *Word) bool {
+	matches, err := VerifyString(word, proposal)
+	if err != nil || dictionary.Len(word) != len(proposal.Chars) {
+		return false
+	}
+	for _, ok := range matches {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// validateChar checks the format of salted letter hashes.
+func validateChar(ch *Char, minSaltLen int) error {
+	if ch == nil {
+		return reject(RejectMalformed, "letter is missing")
+	}
+
+	if l := len(ch.Salt); l < minSaltLen || l > MaxSaltLength {
+		return reject(RejectSalt, "salt length %d is out of bounds [%d, %d]", l, minSaltLen, MaxSaltLength)
+	}
+	if minSaltLen > 0 && !isAlphanumeric(ch.Salt) {
+		return reject(RejectSalt, "salt is not alphanumeric")
+	}
+
+	if len(ch.Hash) != CharHashLength || !isLowerHex(ch.Hash) {
+		return reject(RejectHash, "letter hash is not %d hex chars", CharHashLength)
+	}
+	return nil
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isLowerHex(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'f') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testRules = Rules{MinWordLen: 3, MaxWordLen: 10, MaxAttempts: 5}
+
+func newTestChain(t testing.TB) (parent, next *Header) {
+	g, err := NewGenesis("test", "hello", testRules)
+	require.NoError(t, err)
+
+	parent, err = g.Header()
+	require.NoError(t, err)
+
+	prop, err := NewProposal("world", DefaultSaltLength)
+	require.NoError(t, err)
+
+	next, err = NewHeader(parent, "hello", prop, "peerID")
+	require.NoError(t, err)
+	return parent, next
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(parent, h *Header)
+		reason RejectReason
+	}{
+		{"valid", func(parent, h *Header) {}, 0},
+		{"no proposal", func(parent, h *Header) { h.Proposal = nil }, RejectMalformed},
+		{"nil letter", func(parent, h *Header) { h.Guess.Chars[0] = nil }, RejectMalformed},
+		{"short proposal", func(parent, h *Header) { h.Proposal.Chars = h.Proposal.Chars[:2] }, RejectWordLength},
+		{"short guess", func(parent, h *Header) { h.Guess.Chars = h.Guess.Chars[:2] }, RejectWordLength},
+		{"weak salt", func(parent, h *Header) { h.Proposal.Chars[0].Salt = "a" }, RejectSalt},
+		{"guess salt", func(parent, h *Header) { h.Guess.Chars[0].Salt = "a" }, RejectSalt},
+		{"bad hash", func(parent, h *Header) { h.Proposal.Chars[0].Hash = "xyz" }, RejectHash},
+		{"bad parent hash", func(parent, h *Header) { h.LastHeaderHash = []byte("xyz") }, RejectHash},
+		{"height gap", func(parent, h *Header) { h.Height++ }, RejectHeight},
+		{"absurd height", func(parent, h *Header) { h.Height = 1 << 62 }, RejectHeight},
+		{"wrong parent", func(parent, h *Header) { parent.PeerID = "other" }, RejectParent},
+		{"wrong guess", func(parent, h *Header) { h.Guess.Chars[0].Hash = h.Guess.Chars[1].Hash }, RejectWrongGuess},
+		{"no solution", func(parent, h *Header) { h.Solution = "" }, RejectSolution},
+		{"wrong solution", func(parent, h *Header) { h.Solution = "hallo" }, RejectSolution},
+		{"long solution", func(parent, h *Header) { h.Solution = "hellohellohello" }, RejectWordLength},
+		{"decomposed solution", func(parent, h *Header) { h.Solution = "he\u0301llo" }, RejectSolution},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent, h := newTestChain(t)
+			tt.mutate(parent, h)
+
+			err := h.ValidateBasic(testRules)
+			if err == nil {
+				err = h.ValidateNext(parent, testRules)
+			}
+			require.Equal(t, tt.reason, ReasonOf(err), err)
+		})
+	}
+}
+
+func TestValidateRound(t *testing.T) {
+	rules := testRules
+	rules.RoundTimeout = time.Minute
+	timeout := int64(time.Minute / time.Second)
+
+	_, parent := newTestChain(t)
+	prop, err := NewProposal("round", DefaultSaltLength)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		header func() (*Header, error)
+		after  int64 // seconds after the parent
+		reason RejectReason
+	}{
+		{"solve", func() (*Header, error) { return NewHeader(parent, "world", prop, "solver") }, 0, 0},
+		{"before parent", func() (*Header, error) {
+			return NewHeader(parent, "world", prop, "solver")
+		}, -1, RejectTime},
+		{"early reveal", func() (*Header, error) {
+			return NewHeader(parent, "world", prop, parent.PeerID)
+		}, timeout - 1, RejectTime},
+		{"reveal", func() (*Header, error) { return NewHeader(parent, "world", prop, parent.PeerID) }, timeout, 0},
+		{"early skip", func() (*Header, error) { return NewSkip(parent, prop, "skipper") }, 2*timeout - 1, RejectTime},
+		{"skip", func() (*Header, error) { return NewSkip(parent, prop, "skipper") }, 2 * timeout, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := tt.header()
+			require.NoError(t, err)
+			h.Time = parent.Time + tt.after
+
+			err = h.ValidateBasic(rules)
+			require.NoError(t, err)
+			err = h.ValidateNext(parent, rules)
+			require.Equal(t, tt.reason, ReasonOf(err), err)
+		})
+	}
+}
+
+func FuzzValidate(f *testing.F) {
+	parent, h := newTestChain(f)
+	data, err := json.Marshal(h)
+	require.NoError(f, err)
+
+	f.Add(data)
+	f.Add([]byte(`{"Height":2,"Guess":{"Chars":[null]},"Proposal":{}}`))
+	f.Add([]byte(`{"Height":2,"Guess":{},"Proposal":{"Chars":[{}]}}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		h := &Header{}
+		if json.Unmarshal(data, h) != nil {
+			return
+		}
+
+		// must never panic
+		Verify(h.Guess, parent.Proposal)
+		err := h.ValidateBasic(testRules)
+		if err != nil {
+			require.NotZero(t, ReasonOf(err))
+			return
+		}
+		_ = h.ValidateNext(parent, testRules)
+	})
+}
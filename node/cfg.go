@@ -7,6 +7,7 @@ import (
 	"github.com/BurntSushi/toml"
 
 	"github.com/p2p-games/wordle/node/p2p"
+	"github.com/p2p-games/wordle/wordle"
 )
 
 // ConfigLoader defines a function that loads a config from any source.
@@ -15,20 +16,24 @@ type ConfigLoader func() (*Config, error)
 // Config is main configuration structure for a Node.
 // It combines configuration units for all Node subsystems.
 type Config struct {
-	P2P p2p.Config
+	P2P    p2p.Config
+	Wordle wordle.Config
 }
 
 // DefaultConfig provides a default Config for a given Node Type 'tp'.
-// NOTE: Currently, configs are identical, but this will change.
 func DefaultConfig(tp Type) *Config {
 	switch tp {
 	case Light:
 		return &Config{
-			P2P: p2p.DefaultConfig(),
+			P2P:    p2p.DefaultConfig(),
+			Wordle: wordle.DefaultConfig(),
 		}
 	case Full:
+		wcfg := wordle.DefaultConfig()
+		wcfg.MinFullPeers = 0 // Full Nodes sync from anyone
 		return &Config{
-			P2P: p2p.DefaultConfig(),
+			P2P:    p2p.DefaultConfig(),
+			Wordle: wcfg,
 		}
 	default:
 		panic("node: unknown Node Type")
@@ -5,9 +5,12 @@ import (
 
 	"github.com/ipfs/go-datastore"
 	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/discovery"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	rcmgr "github.com/libp2p/go-libp2p-resource-manager"
 	"go.uber.org/fx"
 
+	"github.com/p2p-games/wordle/model"
 	"github.com/p2p-games/wordle/node/p2p"
 	"github.com/p2p-games/wordle/wordle"
 )
@@ -19,16 +22,116 @@ func baseComponents(cfg *Config, store Store) fx.Option {
 		fx.Supply(store.Config),
 		fx.Provide(store.Datastore),
 		fx.Provide(store.Keystore),
+		fx.Provide(store.Genesis),
 		p2p.Components(cfg.P2P),
+		fx.Decorate(wordleResourceLimits),
 		fx.Provide(wordleService),
+		fx.Provide(wordleDaily),
+		fx.Provide(wordleLanes),
+		fx.Provide(wordleChallenges),
 	)
 }
 
-func wordleService(lc fx.Lifecycle, host core.Host, ds datastore.Batching, pubsub *pubsub.PubSub) *wordle.Service {
-	serv := wordle.NewService(host, ds, pubsub)
+// fullComponents keeps components specific to the Full Node.
+func fullComponents() fx.Option {
+	return fx.Options(
+		fx.Invoke(advertiseFull),
+	)
+}
+
+func wordleService(
+	lc fx.Lifecycle,
+	cfg *Config,
+	genesis *model.Genesis,
+	host core.Host,
+	ds datastore.Batching,
+	pubsub *pubsub.PubSub,
+	disc discovery.Discovery,
+) *wordle.Service {
+	serv := wordle.NewService(cfg.Wordle, genesis, host, ds, pubsub, disc)
 	lc.Append(fx.Hook{
 		OnStart: serv.Start,
 		OnStop:  serv.Stop,
 	})
 	return serv
 }
+
+// wordleLanes plays the main chain of the 'serv' along with the other lanes of the network.
+func wordleLanes(
+	lc fx.Lifecycle,
+	cfg *Config,
+	genesis *model.Genesis,
+	host core.Host,
+	ds datastore.Batching,
+	pubsub *pubsub.PubSub,
+	disc discovery.Discovery,
+	serv *wordle.Service,
+) *wordle.Lanes {
+	lanes := []*wordle.Service{serv}
+	for _, g := range genesis.Lanes()[1:] {
+		lane := wordle.NewService(cfg.Wordle, g, host, ds, pubsub, disc)
+		lc.Append(fx.Hook{
+			OnStart: lane.Start,
+			OnStop:  lane.Stop,
+		})
+		lanes = append(lanes, lane)
+	}
+
+	l := wordle.NewLanes(lanes...)
+	lc.Append(fx.Hook{
+		OnStart: l.Start,
+		OnStop:  l.Stop,
+	})
+	return l
+}
+
+func wordleChallenges(
+	lc fx.Lifecycle,
+	cfg *Config,
+	host core.Host,
+	ds datastore.Batching,
+	serv *wordle.Service,
+) *wordle.Challenges {
+	challenges := wordle.NewChallenges(cfg.Wordle, host, ds, serv)
+	lc.Append(fx.Hook{
+		OnStart: challenges.Start,
+		OnStop:  challenges.Stop,
+	})
+	return challenges
+}
+
+func wordleDaily(
+	lc fx.Lifecycle,
+	genesis *model.Genesis,
+	host core.Host,
+	ds datastore.Batching,
+	pubsub *pubsub.PubSub,
+) *wordle.Daily {
+	daily := wordle.NewDaily(genesis, host, ds, pubsub)
+	lc.Append(fx.Hook{
+		OnStart: daily.Start,
+		OnStop:  daily.Stop,
+	})
+	return daily
+}
+
+// wordleResourceLimits bounds the resources peers can take from the node with the Wordle protocols.
+func wordleResourceLimits(limiter *rcmgr.BasicLimiter, genesis *model.Genesis) *rcmgr.BasicLimiter {
+	for _, lane := range genesis.Lanes() {
+		wordle.SetResourceLimits(limiter, lane.ChainID)
+	}
+	return limiter
+}
+
+// advertiseFull makes the Full Node discoverable by other nodes looking for the whole chain.
+func advertiseFull(ctx context.Context, lc fx.Lifecycle, genesis *model.Genesis, disc discovery.Discovery) {
+	ctx = p2p.WithLifecycle(ctx, lc)
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			for _, lane := range genesis.Lanes() {
+				wordle.Advertise(ctx, disc, lane.ChainID)
+			}
+			return nil
+		},
+	})
+}
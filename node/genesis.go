@@ -0,0 +1,38 @@
+package node
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+// SaveGenesis saves Genesis 'g' under the given 'path'.
+func SaveGenesis(path string, g *model.Genesis) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// LoadGenesis loads and validates Genesis from the given 'path'.
+func LoadGenesis(path string) (*model.Genesis, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var g model.Genesis
+	err = json.NewDecoder(f).Decode(&g)
+	if err != nil {
+		return nil, err
+	}
+
+	return &g, g.Validate()
+}
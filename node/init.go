@@ -1,14 +1,19 @@
 package node
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 
 	"github.com/p2p-games/wordle/libs/fslock"
+	"github.com/p2p-games/wordle/model"
+	"github.com/p2p-games/wordle/wordle"
 )
 
 // Init initializes the Node FileSystem Store for the given Node Type 'tp' in the directory under 'path' with
-// default Config. Options are applied over default Config and persisted on disk.
+// default Config and Genesis. Options are applied over default Config and persisted on disk.
+// Stores created before Genesis files get the default Genesis, and their data is moved into its namespace,
+// except for a chain not descending from it.
 func Init(path string, tp Type) error {
 	cfg := DefaultConfig(tp)
 
@@ -43,6 +48,9 @@ func Init(path string, tp Type) error {
 	}
 
 	cfgPath := configPath(path)
+	gPath := genesisPath(path)
+	// stores without the Genesis file predate it and keep their data out of the namespace of the chain
+	legacy := exists(cfgPath) && !exists(gPath)
 	if !exists(cfgPath) {
 		err = SaveConfig(cfgPath, cfg)
 		if err != nil {
@@ -53,6 +61,25 @@ func Init(path string, tp Type) error {
 		log.Infow("Config already exists", "path", cfgPath)
 	}
 
+	if !exists(gPath) {
+		genesis := wordle.DefaultGenesis()
+		if legacy {
+			// the Genesis is saved only once the data is moved, so that an interrupted move is retried
+			err = migrateData(dataPath(path), genesis)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = SaveGenesis(gPath, genesis)
+		if err != nil {
+			return err
+		}
+		log.Infow("Saving genesis", "path", gPath)
+	} else {
+		log.Infow("Genesis already exists", "path", gPath)
+	}
+
 	log.Info("Node Store initialized")
 	return nil
 }
@@ -73,13 +100,30 @@ func IsInit(path string) bool {
 	}
 
 	if exists(keysPath(path)) &&
-		exists(dataPath(path)) {
+		exists(dataPath(path)) &&
+		exists(genesisPath(path)) {
 		return true
 	}
 
 	return false
 }
 
+// migrateData moves the data kept at the root of the Datastore under 'path' by stores created before Genesis files
+// into the namespace of the chain of the 'genesis', see wordle.MigrateStore.
+func migrateData(path string, genesis *model.Genesis) (err error) {
+	ds, err := openDatastore(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := ds.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	return wordle.MigrateStore(context.Background(), ds, genesis)
+}
+
 const perms = 0755
 
 // initRoot initializes(creates) directory if not created and check if it is writable
@@ -0,0 +1,87 @@
+package node
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/p2p-games/wordle/model"
+	"github.com/p2p-games/wordle/wordle"
+)
+
+func TestInit_Legacy(t *testing.T) {
+	// old nodes played from a random first header over the topic name
+	old, err := model.NewGenesis("wordle", "wordle", wordle.DefaultGenesis().Rules)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		genesis *model.Genesis
+		moved   bool
+	}{
+		{"other genesis", old, false},
+		{"default genesis", wordle.DefaultGenesis(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			path := t.TempDir()
+			require.NoError(t, Init(path, Light))
+			assert.True(t, IsInit(path))
+
+			// make it look like a store created before Genesis files, keeping the chain at the root of the Datastore
+			require.NoError(t, os.Remove(genesisPath(path)))
+			assert.False(t, IsInit(path))
+
+			first, err := tt.genesis.Header()
+			require.NoError(t, err)
+			prop, err := model.NewProposal("world", model.DefaultSaltLength)
+			require.NoError(t, err)
+			head, err := model.NewHeader(first, tt.genesis.ChainID, prop, "player")
+			require.NoError(t, err)
+
+			ds, err := openDatastore(dataPath(path))
+			require.NoError(t, err)
+			store := wordle.NewStore(ds, first)
+			require.NoError(t, store.Init(ctx))
+			require.NoError(t, store.Append(ctx, head))
+			require.NoError(t, ds.Put(ctx, datastore.NewKey("practice/stats"), []byte("stats")))
+			require.NoError(t, ds.Close())
+
+			require.NoError(t, Init(path, Light))
+			assert.True(t, IsInit(path))
+
+			ds, err = openDatastore(dataPath(path))
+			require.NoError(t, err)
+			defer ds.Close()
+
+			g, err := LoadGenesis(genesisPath(path))
+			require.NoError(t, err)
+			genesis, err := g.Header()
+			require.NoError(t, err)
+
+			// the old chain is gone from the root either way
+			_, err = wordle.NewStore(ds, first).Get(ctx, 2)
+			assert.ErrorIs(t, err, datastore.ErrNotFound)
+
+			store = wordle.NewStore(namespace.Wrap(ds, datastore.NewKey(g.ChainID)), genesis)
+			current, err := store.Head(ctx)
+			require.NoError(t, err)
+			if tt.moved {
+				assert.Equal(t, head, current)
+			} else {
+				assert.Equal(t, genesis, current)
+			}
+
+			data, err := ds.Get(ctx, datastore.NewKey("practice/stats"))
+			require.NoError(t, err)
+			assert.Equal(t, "stats", string(data))
+		})
+	}
+}
@@ -35,7 +35,10 @@ type Node struct {
 	DataExchange exchange.Interface
 	DAG          format.DAGService
 
-	Wordle *wordle.Service
+	Wordle     *wordle.Service
+	Lanes      *wordle.Lanes
+	Daily      *wordle.Daily
+	Challenges *wordle.Challenges
 
 	start, stop lifecycleFunc
 }
@@ -49,9 +52,9 @@ func New(tp Type, store Store) (*Node, error) {
 
 	switch tp {
 	case Light:
-		fallthrough
-	case Full:
 		return newNode(baseComponents(cfg, store), fx.Supply(tp))
+	case Full:
+		return newNode(baseComponents(cfg, store), fullComponents(), fx.Supply(tp))
 	default:
 		panic("node: unknown Node Type")
 	}
@@ -8,11 +8,15 @@ import (
 	"github.com/libp2p/go-libp2p-core/connmgr"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/libp2p/go-libp2p-core/pnet"
 	"github.com/libp2p/go-libp2p-core/routing"
 	p2pconfig "github.com/libp2p/go-libp2p/config"
 	routedhost "github.com/libp2p/go-libp2p/p2p/host/routed"
+	tcp "github.com/libp2p/go-tcp-transport"
+	ws "github.com/libp2p/go-ws-transport"
 	"go.uber.org/fx"
 )
 
@@ -32,15 +36,26 @@ func Host(cfg Config) func(hostParams) (HostBase, error) {
 			libp2p.Peerstore(params.PStore),
 			libp2p.ConnectionManager(params.ConnMngr),
 			libp2p.ConnectionGater(params.ConnGater),
+			libp2p.ResourceManager(params.RcMngr),
 			libp2p.UserAgent(fmt.Sprintf("wordle")),
 			libp2p.NATPortMap(), // enables upnp
 			libp2p.DisableRelay(),
 			// to clearly define what defaults we rely upon
 			libp2p.DefaultSecurity,
-			libp2p.DefaultTransports,
 			libp2p.DefaultMuxers,
 		}
 
+		if len(params.PSK) > 0 {
+			opts = append(opts,
+				libp2p.PrivateNetwork(params.PSK),
+				// QUIC does not support private networks
+				libp2p.Transport(tcp.NewTCPTransport),
+				libp2p.Transport(ws.New),
+			)
+		} else {
+			opts = append(opts, libp2p.DefaultTransports)
+		}
+
 		if cfg.Bootstrapper {
 			opts = append(opts, libp2p.EnableNATService())
 		}
@@ -67,8 +82,10 @@ type hostParams struct {
 	Lc        fx.Lifecycle
 	ID        peer.ID
 	Key       crypto.PrivKey
+	PSK       pnet.PSK
 	AddrF     p2pconfig.AddrsFactory
 	PStore    peerstore.Peerstore
 	ConnMngr  connmgr.ConnManager
 	ConnGater connmgr.ConnectionGater
+	RcMngr    network.ResourceManager
 }
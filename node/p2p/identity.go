@@ -2,11 +2,14 @@ package p2p
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
 
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/libp2p/go-libp2p-core/pnet"
 
 	"github.com/p2p-games/wordle/libs/keystore"
 )
@@ -55,3 +58,21 @@ func ID(key crypto.PrivKey, pstore peerstore.Peerstore) (peer.ID, error) {
 
 	return id, pstore.AddPubKey(id, key.GetPublic())
 }
+
+// PSK provides a pre-shared key of the private network, if configured.
+func PSK(cfg Config) func() (pnet.PSK, error) {
+	return func() (pnet.PSK, error) {
+		if cfg.PrivateNetworkKey == "" {
+			return nil, nil
+		}
+
+		psk, err := hex.DecodeString(cfg.PrivateNetworkKey)
+		if err != nil {
+			return nil, fmt.Errorf("failure to decode config.P2P.PrivateNetworkKey: %s", err)
+		}
+		if len(psk) != 32 {
+			return nil, fmt.Errorf("config.P2P.PrivateNetworkKey must be 32 bytes, got %d", len(psk))
+		}
+		return psk, nil
+	}
+}
@@ -0,0 +1,60 @@
+package p2p
+
+import (
+	"context"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p/p2p/discovery/mdns"
+	"go.uber.org/fx"
+)
+
+// mdnsServiceName is the mDNS service name Wordle peers announce themselves under.
+const mdnsServiceName = "wordle"
+
+// MDNS returns invoke function that starts discovering peers in the local network over mDNS, if enabled.
+func MDNS(cfg Config) func(mdnsParams) {
+	return func(params mdnsParams) {
+		if !cfg.MDNS {
+			return
+		}
+
+		ctx := WithLifecycle(params.Ctx, params.Lc)
+		srv := mdns.NewMdnsService(params.Host, mdnsServiceName, &mdnsNotifee{ctx: ctx, host: params.Host})
+		params.Lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				return srv.Start()
+			},
+			OnStop: func(context.Context) error {
+				return srv.Close()
+			},
+		})
+	}
+}
+
+// mdnsNotifee connects to every peer found in the local network.
+type mdnsNotifee struct {
+	ctx  context.Context
+	host host.Host
+}
+
+func (n *mdnsNotifee) HandlePeerFound(pi peer.AddrInfo) {
+	if pi.ID == n.host.ID() {
+		return
+	}
+
+	go func() {
+		err := n.host.Connect(n.ctx, pi)
+		if err != nil {
+			log.Debugw("connecting to local peer", "peer", pi.ID, "err", err)
+		}
+	}()
+}
+
+type mdnsParams struct {
+	fx.In
+
+	Ctx  context.Context
+	Lc   fx.Lifecycle
+	Host host.Host
+}
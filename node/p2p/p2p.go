@@ -1,9 +1,12 @@
 package p2p
 
 import (
+	logging "github.com/ipfs/go-log/v2"
 	"go.uber.org/fx"
 )
 
+var log = logging.Logger("p2p")
+
 // Config combines all configuration fields for P2P subsystem.
 type Config struct {
 	// ListenAddresses - Addresses to listen to on local NIC.
@@ -15,6 +18,14 @@ type Config struct {
 	NoAnnounceAddresses []string
 	// Bootstrapper is flag telling this node is a bootstrapper.
 	Bootstrapper bool
+	// BootstrapPeers - Addresses of peers to bootstrap from. If empty, public IPFS bootstrap peers are used,
+	// unless the network is private. Otherwise, peers with private addresses are kept in the DHT too.
+	BootstrapPeers []string
+	// PrivateNetworkKey - Hex encoded 32 bytes pre-shared key of a private network. Only peers knowing the key can
+	// connect to each other. Empty means the public network.
+	PrivateNetworkKey string
+	// MDNS enables discovery of peers in the local network.
+	MDNS bool
 	// ConnManager is a configuration tuple for ConnectionManager.
 	ConnManager ConnManagerConfig
 }
@@ -32,8 +43,11 @@ func DefaultConfig() Config {
 			"/ip4/127.0.0.1/tcp/2121",
 			"/ip6/::/tcp/2121",
 		},
-		Bootstrapper: false,
-		ConnManager:  DefaultConnManagerConfig(),
+		Bootstrapper:      false,
+		BootstrapPeers:    []string{},
+		PrivateNetworkKey: "",
+		MDNS:              false,
+		ConnManager:       DefaultConnManagerConfig(),
 	}
 }
 
@@ -41,10 +55,13 @@ func DefaultConfig() Config {
 func Components(cfg Config) fx.Option {
 	return fx.Options(
 		fx.Provide(Key),
+		fx.Provide(PSK(cfg)),
 		fx.Provide(ID),
 		fx.Provide(PeerStore),
 		fx.Provide(ConnectionManager(cfg)),
 		fx.Provide(ConnectionGater),
+		fx.Provide(ResourceLimiter),
+		fx.Provide(ResourceManager),
 		fx.Provide(Host(cfg)),
 		fx.Provide(RoutedHost),
 		fx.Provide(PubSub(cfg)),
@@ -55,5 +72,6 @@ func Components(cfg Config) fx.Option {
 		fx.Provide(Discovery),
 		fx.Provide(AddrsFactory(cfg.AnnounceAddresses, cfg.NoAnnounceAddresses)),
 		fx.Invoke(Listen(cfg.ListenAddresses)),
+		fx.Invoke(MDNS(cfg)),
 	)
 }
@@ -0,0 +1,28 @@
+package p2p
+
+import (
+	"context"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	rcmgr "github.com/libp2p/go-libp2p-resource-manager"
+	"go.uber.org/fx"
+)
+
+// ResourceLimiter provides the limits of the resource manager scaled to the system memory.
+// Services may add limits for their protocols to it, before the resource manager is constructed.
+func ResourceLimiter() *rcmgr.BasicLimiter {
+	return rcmgr.NewDefaultLimiter()
+}
+
+// ResourceManager constructs the resource manager accounting streams, connections and memory of the Host.
+func ResourceManager(lc fx.Lifecycle, limiter *rcmgr.BasicLimiter) (network.ResourceManager, error) {
+	mgr, err := rcmgr.NewResourceManager(limiter)
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{OnStop: func(context.Context) error {
+		return mgr.Close()
+	}})
+	return mgr, nil
+}
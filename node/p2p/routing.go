@@ -2,12 +2,16 @@ package p2p
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ipfs/go-datastore"
 	idiscovery "github.com/libp2p/go-libp2p-core/discovery"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/pnet"
 	"github.com/libp2p/go-libp2p-core/routing"
 	discovery "github.com/libp2p/go-libp2p-discovery"
 	dht "github.com/libp2p/go-libp2p-kad-dht"
+	ma "github.com/multiformats/go-multiaddr"
 	"go.uber.org/fx"
 )
 
@@ -19,14 +23,33 @@ func ContentRouting(router routing.PeerRouting) routing.ContentRouting {
 // Basically, this provides a way to discover peer addresses by respecting public keys.
 func PeerRouting(cfg Config) func(routingParams) (routing.PeerRouting, error) {
 	return func(params routingParams) (routing.PeerRouting, error) {
+		bpeers, err := bootstrapPeers(cfg.BootstrapPeers)
+		if err != nil {
+			return nil, err
+		}
+
 		opts := []dht.Option{
 			dht.Mode(dht.ModeAuto),
 			dht.Datastore(params.DataStore),
-			dht.QueryFilter(dht.PublicQueryFilter),
-			dht.RoutingTableFilter(dht.PublicRoutingTableFilter),
-			dht.BootstrapPeers(dht.GetDefaultBootstrapPeerAddrInfos()...),
 		}
 
+		switch {
+		case len(params.PSK) > 0:
+			// private networks are usually not reachable publicly, so we must serve regardless
+			opts = append(opts, dht.Mode(dht.ModeServer))
+		case len(bpeers) > 0:
+			// custom bootstrappers, e.g. of a league without a PSK, may be in a private network with the peers,
+			// so private addresses must not be filtered out
+		default:
+			// public network
+			opts = append(opts,
+				dht.QueryFilter(dht.PublicQueryFilter),
+				dht.RoutingTableFilter(dht.PublicRoutingTableFilter),
+			)
+			bpeers = dht.GetDefaultBootstrapPeerAddrInfos()
+		}
+		opts = append(opts, dht.BootstrapPeers(bpeers...))
+
 		if cfg.Bootstrapper {
 			// override options for bootstrapper
 			opts = append(opts,
@@ -55,6 +78,20 @@ func Discovery(r routing.ContentRouting) idiscovery.Discovery {
 	return discovery.NewRoutingDiscovery(r)
 }
 
+// bootstrapPeers parses the given bootstrap peer addresses.
+func bootstrapPeers(addrs []string) ([]peer.AddrInfo, error) {
+	maddrs := make([]ma.Multiaddr, len(addrs))
+	for i, addr := range addrs {
+		maddr, err := ma.NewMultiaddr(addr)
+		if err != nil {
+			return nil, fmt.Errorf("failure to parse config.P2P.BootstrapPeers: %s", err)
+		}
+		maddrs[i] = maddr
+	}
+
+	return peer.AddrInfosFromP2pAddrs(maddrs...)
+}
+
 type routingParams struct {
 	fx.In
 
@@ -62,4 +99,5 @@ type routingParams struct {
 	Lc        fx.Lifecycle
 	Host      HostBase
 	DataStore datastore.Batching
+	PSK       pnet.PSK
 }
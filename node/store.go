@@ -13,6 +13,7 @@ import (
 
 	"github.com/p2p-games/wordle/libs/fslock"
 	"github.com/p2p-games/wordle/libs/keystore"
+	"github.com/p2p-games/wordle/model"
 )
 
 var (
@@ -40,6 +41,9 @@ type Store interface {
 	// PutConfig alters the stored Node config.
 	PutConfig(*Config) error
 
+	// Genesis loads the stored Genesis of the network the Node plays in.
+	Genesis() (*model.Genesis, error)
+
 	// Close closes the Store freeing up acquired resources and locks.
 	Close() error
 }
@@ -96,6 +100,15 @@ func (f *fsStore) PutConfig(cfg *Config) error {
 	return nil
 }
 
+func (f *fsStore) Genesis() (*model.Genesis, error) {
+	g, err := LoadGenesis(genesisPath(f.path))
+	if err != nil {
+		return nil, fmt.Errorf("node: can't load Genesis: %w", err)
+	}
+
+	return g, nil
+}
+
 func (f *fsStore) Keystore() (_ keystore.Keystore, err error) {
 	f.lock.RLock()
 	if f.keys != nil {
@@ -126,6 +139,16 @@ func (f *fsStore) Datastore() (_ datastore.Batching, err error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
+	f.data, err = openDatastore(dataPath(f.path))
+	if err != nil {
+		return nil, err
+	}
+
+	return f.data, nil
+}
+
+// openDatastore opens the Badger Datastore in the directory under the given 'path'.
+func openDatastore(path string) (datastore.Batching, error) {
 	opts := dsbadger.DefaultOptions // this should be copied
 
 	// Badger sets ValueThreshold to 1K by default and this makes shares being stored in LSM tree
@@ -149,12 +172,12 @@ func (f *fsStore) Datastore() (_ datastore.Batching, err error) {
 	// Currently, we only append data on disk without removing.
 	opts.GcInterval = 0
 
-	f.data, err = dsbadger.NewDatastore(dataPath(f.path), &opts)
+	ds, err := dsbadger.NewDatastore(path, &opts)
 	if err != nil {
 		return nil, fmt.Errorf("node: can't open Badger Datastore: %w", err)
 	}
 
-	return f.data, nil
+	return ds, nil
 }
 
 func (f *fsStore) Close() error {
@@ -180,6 +203,10 @@ func configPath(base string) string {
 	return filepath.Join(base, "config.toml")
 }
 
+func genesisPath(base string) string {
+	return filepath.Join(base, "genesis.json")
+}
+
 func lockPath(base string) string {
 	return filepath.Join(base, "lock")
 }
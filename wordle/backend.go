@@ -0,0 +1,42 @@
+package wordle
+
+import (
+	"context"
+
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+// GameBackend runs the rounds WordGames are played in, e.g. over the network with the Service,
+// or locally with the MemoryBackend.
+type GameBackend interface {
+	// ID identifies the local player in headers.
+	ID() string
+	// Genesis returns the Genesis of the game, defining its rules.
+	Genesis() *model.Genesis
+	// CurrentRound returns the header starting the current round.
+	CurrentRound(context.Context) (*model.Header, error)
+	// SubmitGuess submits the 'guess' of the current word, proposing the 'proposal' next.
+	SubmitGuess(ctx context.Context, guess, proposal string) error
+	// Timeout reveals our current word or skips someone's, once the round timed out,
+	// proposing the 'proposal' next.
+	Timeout(ctx context.Context, proposal string) error
+	// Rounds returns up to 'limit' latest finished rounds, starting from the latest one.
+	Rounds(ctx context.Context, limit int) ([]*model.Round, error)
+	// Events returns a channel receiving Events until the 'ctx' is done.
+	Events(ctx context.Context) <-chan Event
+	// SaveGame keeps the 'state' of the local player's game in the round started by the header with the 'round' hash.
+	SaveGame(ctx context.Context, round multihash.Multihash, state *GameState) error
+	// LoadGame returns the state of the local player's game in the round started by the header with the 'round' hash.
+	LoadGame(ctx context.Context, round multihash.Multihash) (*GameState, error)
+}
+
+var (
+	_ GameBackend = (*Service)(nil)
+	_ GameBackend = (*MemoryBackend)(nil)
+	_ GameBackend = (*Daily)(nil)
+	_ GameBackend = (*Lanes)(nil)
+	_ GameBackend = (*Challenges)(nil)
+	_ GameBackend = (*Team)(nil)
+)
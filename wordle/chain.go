@@ -0,0 +1,128 @@
+package wordle
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+// errStaleHead is returned when a head does not advance the chain.
+var errStaleHead = errors.New("wordle: the head is not higher than ours")
+
+// chain is the single writer of the local chain.
+// It owns the head, applies candidate headers one at a time, so that racing candidates cannot overwrite each other,
+// and emits head changes to the events.
+type chain struct {
+	store      *Store
+	rules      model.Rules
+	events     *eventBus
+	candidates chan *candidate
+
+	headLk sync.RWMutex
+	head   *model.Header
+}
+
+// candidate is a request to the chain to move its head.
+type candidate struct {
+	// headers to append in order, where the first one follows the current head
+	headers []*model.Header
+	result  chan error
+}
+
+func newChain(store *Store, rules model.Rules, events *eventBus) *chain {
+	return &chain{
+		store:      store,
+		rules:      rules,
+		events:     events,
+		candidates: make(chan *candidate),
+	}
+}
+
+// start loads the head from the Store and runs the chain until the 'ctx' is done.
+func (c *chain) start(ctx context.Context) error {
+	head, err := c.store.Head(ctx)
+	if err != nil {
+		return err
+	}
+	c.setHead(head)
+
+	go c.run(ctx)
+	return nil
+}
+
+func (c *chain) run(ctx context.Context) {
+	for {
+		select {
+		case cand := <-c.candidates:
+			cand.result <- c.apply(ctx, cand)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Head returns the current head of the chain.
+func (c *chain) Head() *model.Header {
+	c.headLk.RLock()
+	defer c.headLk.RUnlock()
+	return c.head
+}
+
+// extend appends the given 'headers' on top of the head, if they link to it and to each other.
+// The first header wins when several candidates extend the same head.
+func (c *chain) extend(ctx context.Context, headers ...*model.Header) error {
+	return c.submit(ctx, &candidate{headers: headers})
+}
+
+func (c *chain) submit(ctx context.Context, cand *candidate) error {
+	if len(cand.headers) == 0 {
+		return nil
+	}
+
+	cand.result = make(chan error, 1)
+	select {
+	case c.candidates <- cand:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-cand.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// apply chooses whether the candidate becomes the new head and stores it.
+// Only the run loop calls it, so the head cannot change in between.
+func (c *chain) apply(ctx context.Context, cand *candidate) error {
+	parent := c.Head()
+	for _, h := range cand.headers {
+		err := h.ValidateNext(parent, c.rules)
+		if err != nil {
+			return err
+		}
+		parent = h
+	}
+
+	for _, h := range cand.headers {
+		err := c.store.Append(ctx, h)
+		if err != nil {
+			return err
+		}
+	}
+
+	newHead := cand.headers[len(cand.headers)-1]
+	c.setHead(newHead)
+	c.events.emit(EvtNewHead{Head: newHead})
+	return nil
+}
+
+func (c *chain) setHead(head *model.Header) {
+	c.headLk.Lock()
+	defer c.headLk.Unlock()
+	c.head = head
+}
@@ -0,0 +1,71 @@
+package wordle
+
+import (
+	"context"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+func TestChain(t *testing.T) {
+	const racers = 8
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	genesis, err := DefaultGenesis().Header()
+	require.NoError(t, err)
+
+	events := newEventBus()
+	c := newChain(NewStore(sync.MutexWrap(datastore.NewMapDatastore()), genesis), DefaultGenesis().Rules, events)
+	err = c.start(ctx)
+	require.NoError(t, err)
+	evts := events.subscribe(ctx)
+
+	// racing solutions for the same head
+	candidates := make([]*model.Header, racers)
+	for i := range candidates {
+		prop, err := model.NewProposal("apple", model.DefaultSaltLength)
+		require.NoError(t, err)
+
+		candidates[i], err = model.NewHeader(genesis, DefaultGenesis().ChainID, prop, "racer")
+		require.NoError(t, err)
+	}
+
+	var (
+		wg      gosync.WaitGroup
+		winners int32
+		lk      gosync.Mutex
+	)
+	for _, h := range candidates {
+		wg.Add(1)
+		go func(h *model.Header) {
+			defer wg.Done()
+			if c.extend(ctx, h) == nil {
+				lk.Lock()
+				winners++
+				lk.Unlock()
+			}
+		}(h)
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, winners)
+
+	head := (<-evts).(EvtNewHead).Head
+	assert.Equal(t, head, c.Head())
+	stored, err := c.store.Head(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, head, stored)
+
+	// headers not linking to the head never replace it, however high they are
+	err = c.extend(ctx, &model.Header{Height: 10, Proposal: &model.Word{}})
+	assert.Error(t, err)
+	assert.Equal(t, head, c.Head())
+}
@@ -0,0 +1,455 @@
+package wordle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/dictionary"
+	"github.com/p2p-games/wordle/model"
+)
+
+// challengeTimeout limits the time of sending a single challenge or result.
+var challengeTimeout = time.Second * 10
+
+// maxChallengeSize bounds the amount of bytes we read from a challenge stream.
+const maxChallengeSize = 64 << 10
+
+// maxPendingChallenges bounds the amount of received challenges waiting to be played.
+// Every peer may have only one of them pending.
+const maxPendingChallenges = 16
+
+var (
+	errNoChallenge        = errors.New("wordle: no challenge to play")
+	errChallengeNoTimeout = errors.New("wordle: challenges can not be revealed or skipped")
+)
+
+// challengeState is the challenge being played with our guesses so far, saved to continue it after a restart.
+type challengeState struct {
+	Challenge *model.Challenge
+	Guesses   []string `json:",omitempty"`
+}
+
+// challengeMsg is sent over challenge streams. It is either a new Challenge or the Result of one we sent.
+type challengeMsg struct {
+	Challenge *model.Challenge       `json:",omitempty"`
+	Result    *model.ChallengeResult `json:",omitempty"`
+}
+
+// Challenges is a GameBackend for direct challenges between two players.
+// The challenger commits to a word for a specific peer over a libp2p stream. The challenged player plays it
+// as any other round, and sends the signed result back, optionally publishing it on the main topic as a record.
+type Challenges struct {
+	cfg     Config
+	genesis *model.Genesis // with the rules of challenges
+	host    core.Host
+	key     crypto.PrivKey
+	protoID protocol.ID
+	ds      datastore.Datastore
+	store   *Store // keeps the games
+	serv    *Service
+	events  *eventBus
+
+	lk      sync.Mutex
+	pending []*model.Challenge // received and not played yet
+	current *model.Challenge
+	guesses []string
+	// restored is true, if the current challenge is restored after a restart and not resumed yet
+	restored bool
+
+	log func(string)
+}
+
+// NewChallenges constructs the direct challenges of the network of the Service 'serv'.
+func NewChallenges(cfg Config, host core.Host, ds datastore.Batching, serv *Service) *Challenges {
+	if cfg.SaltLength == 0 {
+		cfg.SaltLength = DefaultConfig().SaltLength
+	}
+
+	genesis := serv.Genesis()
+	head, err := genesis.Header()
+	if err != nil {
+		panic(err)
+	}
+
+	ds = namespace.Wrap(ds, datastore.NewKey(genesis.ChainID).ChildString("challenges"))
+	return &Challenges{
+		cfg:     cfg,
+		genesis: genesis,
+		host:    host,
+		key:     host.Peerstore().PrivKey(host.ID()),
+		protoID: protocolID(genesis.ChainID) + "/challenge",
+		ds:      ds,
+		store:   NewStore(ds, head),
+		serv:    serv,
+		events:  newEventBus(),
+
+		log: func(s string) { fmt.Println(s) },
+	}
+}
+
+func (c *Challenges) SetLog(log func(string)) {
+	c.log = log
+}
+
+func (c *Challenges) Start(ctx context.Context) error {
+	data, err := c.ds.Get(ctx, currentKey)
+	switch err {
+	case nil:
+		state := &challengeState{}
+		err = json.Unmarshal(data, state)
+		if err != nil {
+			return err
+		}
+
+		c.current, c.guesses, c.restored = state.Challenge, state.Guesses, true
+		c.log(fmt.Sprintf("The challenge of %s is not finished, type %s to continue it",
+			state.Challenge.From, acceptCmd))
+	case datastore.ErrNotFound:
+	default:
+		return err
+	}
+
+	c.host.SetStreamHandler(c.protoID, c.handle)
+	return nil
+}
+
+func (c *Challenges) Stop(context.Context) error {
+	c.host.RemoveStreamHandler(c.protoID)
+	return nil
+}
+
+// Send challenges the peer 'to' to guess the 'word'.
+func (c *Challenges) Send(ctx context.Context, to peer.ID, word string) error {
+	word = dictionary.Normalize(word)
+	rules := c.genesis.Rules
+	if l := dictionary.Len(word); l < rules.MinWordLen || l > rules.MaxWordLen {
+		return fmt.Errorf("wordle: the word must be from %d to %d letters long", rules.MinWordLen, rules.MaxWordLen)
+	}
+	language := rules.Dictionary
+	if language == "" {
+		language = wordLanguage(word)
+	}
+
+	ch, err := model.NewChallenge(to.String(), word, language, rules.MaxAttempts, c.cfg.SaltLength, c.key)
+	if err != nil {
+		return err
+	}
+	hash, err := ch.Hash()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(ch)
+	if err != nil {
+		return err
+	}
+	// keep what we sent, so that only results of our challenges are accepted
+	err = c.ds.Put(ctx, sentKey.ChildString(hash.B58String()), data)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, to, &challengeMsg{Challenge: ch})
+}
+
+// wordLanguage returns the first built-in language with the 'word' in its alphabet, or the default one.
+func wordLanguage(word string) string {
+	for _, l := range dictionary.Languages() {
+		alphabet, err := dictionary.GetAlphabet(l)
+		if err == nil && alphabet.Contains(word) {
+			return l
+		}
+	}
+	return DefaultDailyDictionary
+}
+
+// Accept starts playing the next received challenge, if not playing one already,
+// or continues the one played before a restart.
+func (c *Challenges) Accept(ctx context.Context) error {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if c.restored {
+		c.restored = false
+		c.events.emit(EvtNewHead{Head: c.current.Header()})
+		return nil
+	}
+	if c.current != nil {
+		return fmt.Errorf("wordle: finish the challenge of %s first", c.current.From)
+	}
+	if len(c.pending) == 0 {
+		return errNoChallenge
+	}
+
+	c.current, c.guesses = c.pending[0], nil
+	err := c.saveCurrent(ctx)
+	if err != nil {
+		c.current = nil
+		return err
+	}
+	c.pending = c.pending[1:]
+	c.events.emit(EvtNewHead{Head: c.current.Header()})
+	return nil
+}
+
+// saveCurrent persists the challenge being played, or forgets it, once it is finished.
+// Must be called under the lock.
+func (c *Challenges) saveCurrent(ctx context.Context) error {
+	if c.current == nil {
+		return c.ds.Delete(ctx, currentKey)
+	}
+
+	data, err := json.Marshal(&challengeState{Challenge: c.current, Guesses: c.guesses})
+	if err != nil {
+		return err
+	}
+	return c.ds.Put(ctx, currentKey, data)
+}
+
+// Pending returns the received challenges not played yet.
+func (c *Challenges) Pending() []*model.Challenge {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return append([]*model.Challenge(nil), c.pending...)
+}
+
+// ID identifies the local player in challenges.
+func (c *Challenges) ID() string {
+	return c.host.ID().String()
+}
+
+// Genesis returns the Genesis of the network with the rules of the challenge being played.
+// Its dictionary is of the language of the challenge, which gives the alphabet of the guesses,
+// so that the challenged player goes straight to guessing.
+func (c *Challenges) Genesis() *model.Genesis {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if c.current == nil {
+		return c.genesis
+	}
+
+	g := *c.genesis
+	g.Rules.Dictionary = c.current.Language
+	return &g
+}
+
+// CurrentRound returns the header of the challenge being played.
+func (c *Challenges) CurrentRound(context.Context) (*model.Header, error) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if c.current == nil {
+		return nil, errNoChallenge
+	}
+	return c.current.Header(), nil
+}
+
+// Events returns a channel receiving Events until the 'ctx' is done.
+func (c *Challenges) Events(ctx context.Context) <-chan Event {
+	return c.events.subscribe(ctx)
+}
+
+// SubmitGuess checks the 'guess' of the challenge being played and sends our result, once we are done with it.
+// There is nothing to propose in challenges.
+func (c *Challenges) SubmitGuess(ctx context.Context, guess, _ string) error {
+	c.lk.Lock()
+	ch := c.current
+	if ch == nil {
+		c.lk.Unlock()
+		return errNoChallenge
+	}
+
+	h, err := model.NewHeader(ch.Header(), guess, nil, c.ID())
+	if err != nil {
+		c.lk.Unlock()
+		return err
+	}
+	solved := model.Verify(h.Guess, ch.Word)
+	c.events.emit(EvtGuessAttempt{Header: h, Successful: solved})
+
+	c.guesses = append(c.guesses, h.Solution)
+	if !solved && len(c.guesses) < ch.MaxAttempts {
+		err = c.saveCurrent(ctx)
+		c.lk.Unlock()
+		return err
+	}
+
+	res, err := model.NewChallengeResult(ch, c.guesses, c.key)
+	c.current, c.guesses, c.restored = nil, nil, false
+	if err == nil {
+		err = c.saveCurrent(ctx)
+	}
+	c.lk.Unlock()
+	if err != nil {
+		return err
+	}
+
+	c.events.emit(EvtChallengeResult{Result: res})
+	from, err := peer.Decode(ch.From)
+	if err != nil {
+		return err
+	}
+	err = c.send(ctx, from, &challengeMsg{Result: res})
+	if err != nil {
+		log.Warnw("sending challenge result", "peer", from, "err", err)
+		c.log(fmt.Sprintf("unable to send the result to %s", from))
+	}
+	if c.cfg.PublishChallenges {
+		return c.serv.PublishChallengeResult(ctx, res)
+	}
+	return nil
+}
+
+// Timeout is not possible in challenges.
+func (c *Challenges) Timeout(context.Context, string) error {
+	return errChallengeNoTimeout
+}
+
+// Rounds returns no rounds, as every challenge is a game of its own.
+func (c *Challenges) Rounds(context.Context, int) ([]*model.Round, error) {
+	return nil, nil
+}
+
+// SaveGame keeps the 'state' of the local player's game in the challenge with the header of the 'round' hash.
+func (c *Challenges) SaveGame(ctx context.Context, round multihash.Multihash, state *GameState) error {
+	return c.store.PutGame(ctx, round, state)
+}
+
+// LoadGame returns the state of the local player's game in the challenge with the header of the 'round' hash.
+func (c *Challenges) LoadGame(ctx context.Context, round multihash.Multihash) (*GameState, error) {
+	return c.store.Game(ctx, round)
+}
+
+// send sends the 'msg' to the peer 'p'.
+func (c *Challenges) send(ctx context.Context, p peer.ID, msg *challengeMsg) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, challengeTimeout)
+	defer cancel()
+
+	stream, err := c.host.NewStream(ctx, p, c.protoID)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	deadline, _ := ctx.Deadline()
+	_ = stream.SetDeadline(deadline)
+
+	_, err = stream.Write(data)
+	if err != nil {
+		_ = stream.Reset()
+		return err
+	}
+	return stream.CloseWrite()
+}
+
+// handle receives a challenge or the result of one we sent.
+func (c *Challenges) handle(stream network.Stream) {
+	defer stream.Close()
+	_ = stream.SetDeadline(time.Now().Add(challengeTimeout))
+
+	p := stream.Conn().RemotePeer()
+	data, err := io.ReadAll(io.LimitReader(stream, maxChallengeSize))
+	if err != nil {
+		log.Debugw("receiving challenge", "peer", p, "err", err)
+		return
+	}
+
+	msg := &challengeMsg{}
+	err = json.Unmarshal(data, msg)
+	if err != nil {
+		log.Errorw("unmarshalling challenge", "peer", p, "err", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), challengeTimeout)
+	defer cancel()
+	switch {
+	case msg.Challenge != nil:
+		err = c.receive(p, msg.Challenge)
+	case msg.Result != nil:
+		err = c.receiveResult(ctx, p, msg.Result)
+	default:
+		err = errors.New("wordle: empty challenge message")
+	}
+	if err != nil {
+		log.Errorw("invalid challenge", "peer", p, "err", err)
+	}
+}
+
+// receive queues the Challenge 'ch' sent by the peer 'p'.
+func (c *Challenges) receive(p peer.ID, ch *model.Challenge) error {
+	if ch.From != p.String() || ch.To != c.ID() {
+		return fmt.Errorf("wordle: challenge from %s to %s sent by %s", ch.From, ch.To, p)
+	}
+	err := ch.Validate(c.genesis.Rules)
+	if err != nil {
+		return err
+	}
+
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if len(c.pending) >= maxPendingChallenges {
+		return fmt.Errorf("wordle: %d challenges are pending already", len(c.pending))
+	}
+	for _, pending := range c.pending {
+		if pending.From == ch.From {
+			return fmt.Errorf("wordle: a challenge of %s is pending already", ch.From)
+		}
+	}
+
+	c.pending = append(c.pending, ch)
+	c.events.emit(EvtChallenge{Challenge: ch})
+	return nil
+}
+
+// receiveResult keeps the result of a challenge we sent to the peer 'p'.
+func (c *Challenges) receiveResult(ctx context.Context, p peer.ID, res *model.ChallengeResult) error {
+	err := res.Validate(c.genesis.Rules)
+	if err != nil {
+		return err
+	}
+	if res.Challenge.To != p.String() || res.Challenge.From != c.ID() {
+		return fmt.Errorf("wordle: result of challenge to %s sent by %s", res.Challenge.To, p)
+	}
+
+	hash, err := res.Challenge.Hash()
+	if err != nil {
+		return err
+	}
+	key := sentKey.ChildString(hash.B58String())
+	has, err := c.ds.Has(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return errors.New("wordle: result of an unknown challenge")
+	}
+
+	err = c.ds.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+	c.events.emit(EvtChallengeResult{Result: res})
+	return nil
+}
+
+var (
+	sentKey    = datastore.NewKey("sent")
+	currentKey = datastore.NewKey("current")
+)
@@ -0,0 +1,129 @@
+package wordle
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+func TestChallenges(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// challenges are signed, so peers need real keys
+	net := mocknet.New()
+	for i := 0; i < 2; i++ {
+		key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(t, err)
+		_, err = net.AddPeer(key, ma.StringCast(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", 4200+i)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, net.LinkAll())
+
+	cfg := DefaultConfig()
+	cfg.PublishChallenges = true
+	servs := make([]*Service, 2)
+	challenges := make([]*Challenges, 2)
+	dss := make([]datastore.Batching, 2)
+	for i, h := range net.Hosts() {
+		ds := sync.MutexWrap(datastore.NewMapDatastore())
+		dss[i] = ds
+		ps, err := pubsub.NewFloodSub(ctx, h, pubsub.WithMessageSignaturePolicy(pubsub.StrictNoSign))
+		require.NoError(t, err)
+
+		servs[i] = NewService(cfg, DefaultGenesis(), h, ds, ps, nil)
+		servs[i].SetLog(func(string) {})
+		require.NoError(t, servs[i].Start(ctx))
+		challenges[i] = NewChallenges(cfg, h, ds, servs[i])
+		challenges[i].SetLog(func(string) {})
+		require.NoError(t, challenges[i].Start(ctx))
+	}
+	require.NoError(t, net.ConnectAllButSelf())
+	for _, serv := range servs {
+		select {
+		case <-serv.bootsrapped:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	challenger, player := challenges[0], challenges[1]
+	challengerEvts, playerEvts := challenger.Events(ctx), player.Events(ctx)
+	records := servs[0].Events(ctx)
+
+	require.ErrorIs(t, player.Accept(ctx), errNoChallenge)
+	require.Error(t, challenger.Send(ctx, net.Hosts()[1].ID(), "hi"))
+	require.NoError(t, challenger.Send(ctx, net.Hosts()[1].ID(), "hello"))
+	assert.Equal(t, "", challenger.Genesis().Rules.Dictionary)
+
+	evt := nextEvent(ctx, t, playerEvts).(EvtChallenge)
+	assert.Equal(t, challenger.ID(), evt.Challenge.From)
+	require.Len(t, player.Pending(), 1)
+
+	// every peer has only one challenge pending
+	from := net.Hosts()[0].ID()
+	again, err := model.NewChallenge(player.ID(), "world", "en", challenger.Genesis().Rules.MaxAttempts,
+		model.DefaultSaltLength, net.Hosts()[0].Peerstore().PrivKey(from))
+	require.NoError(t, err)
+	require.Error(t, player.receive(from, again))
+	require.Len(t, player.Pending(), 1)
+
+	// the challenge is played as any other round
+	require.NoError(t, player.Accept(ctx))
+	assert.Equal(t, "en", player.Genesis().Rules.Dictionary)
+	head := nextEvent(ctx, t, playerEvts).(EvtNewHead).Head
+	game := NewWordGame(ctx, player, head)
+	require.Equal(t, StateGuessing, game.State())
+	require.NoError(t, game.NewStdinInput("world"))
+
+	// and continued after a restart
+	player = NewChallenges(cfg, net.Hosts()[1], dss[1], servs[1])
+	player.SetLog(func(string) {})
+	require.NoError(t, player.Start(ctx))
+	playerEvts = player.Events(ctx)
+	require.NoError(t, player.Accept(ctx))
+	head = nextEvent(ctx, t, playerEvts).(EvtNewHead).Head
+	game = NewWordGame(ctx, player, head)
+	require.NoError(t, game.Restore())
+	require.Equal(t, []string{"world"}, game.AttemptedWords())
+	require.NoError(t, game.NewStdinInput("hello"))
+	require.Equal(t, StateSolved, game.State())
+
+	// both the challenger and everyone else get the result
+	for _, events := range []<-chan Event{challengerEvts, records} {
+		for {
+			res, ok := nextEvent(ctx, t, events).(EvtChallengeResult)
+			if ok {
+				assert.True(t, res.Result.Solved())
+				assert.Equal(t, []string{".y.g.", "ggggg"}, res.Result.Grid())
+				break
+			}
+		}
+	}
+	require.ErrorIs(t, player.Accept(ctx), errNoChallenge)
+}
+
+func TestWordLanguage(t *testing.T) {
+	for word, language := range map[string]string{
+		"hello":  "en",
+		"cañón":  "es",
+		"straße": "de",
+		"привет": "ru",
+		"hi5":    DefaultDailyDictionary,
+	} {
+		assert.Equal(t, language, wordLanguage(word), word)
+	}
+}
@@ -0,0 +1,53 @@
+package wordle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/protocol"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+// Config combines all configuration fields for the Wordle Service.
+type Config struct {
+	// MinFullPeers is the minimum amount of Full Node peers to discover before syncing.
+	// Full Nodes are preferred for sync and dispute resolution, as they hold the whole chain.
+	MinFullPeers int
+	// FullPeersTimeout limits how long to wait for MinFullPeers before syncing with any available peers.
+	FullPeersTimeout time.Duration
+	// SaltLength is the length of random salts for letters of proposed words.
+	// Longer salts are harder to brute force. Zero means model.DefaultSaltLength.
+	SaltLength int
+	// RequestRate is the amount of header requests per second a peer may send us on average.
+	RequestRate float64
+	// RequestBurst is the amount of header requests a peer may send us at once.
+	RequestBurst int
+	// MaxConcurrentRequests bounds the amount of header requests served at the same time for all peers.
+	// Zero values of the request limits mean the defaults.
+	MaxConcurrentRequests int
+	// PublishChallenges makes the signed results of challenges we played public on the main topic, as a record.
+	PublishChallenges bool
+	// HardMode makes the rounds of the words we propose hard, requiring guesses to use the revealed hints.
+	HardMode bool
+	// WordLen is the length in letters of the words we propose. In networks with a dictionary, our words are selected
+	// among its words of the length. Zero means any length.
+	WordLen int
+}
+
+// DefaultConfig returns default configuration for the Wordle Service.
+func DefaultConfig() Config {
+	return Config{
+		MinFullPeers:          1,
+		FullPeersTimeout:      time.Second * 30,
+		SaltLength:            model.DefaultSaltLength,
+		RequestRate:           10,
+		RequestBurst:          20,
+		MaxConcurrentRequests: 32,
+	}
+}
+
+// protocolID derives the ID of the header exchange protocol for the given 'chainID'.
+func protocolID(chainID string) protocol.ID {
+	return protocol.ID(fmt.Sprintf("/%s/v0.0.1", chainID))
+}
@@ -0,0 +1,451 @@
+package wordle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/dictionary"
+	"github.com/p2p-games/wordle/model"
+)
+
+// dailyPeer proposes the daily puzzles.
+const dailyPeer = "daily"
+
+// DefaultDailyDictionary is the dictionary of daily puzzles on networks without a dictionary.
+const DefaultDailyDictionary = "en"
+
+// dailyRollInterval is how often Daily checks whether the next day started.
+var dailyRollInterval = time.Minute
+
+var errDailyNoTimeout = errors.New("wordle: the daily puzzle can not be revealed or skipped")
+
+// Daily is a GameBackend for the classic puzzle of the day, played alongside the chain of the same genesis.
+// Every node derives the word of the UTC day from the genesis hash, so there is nothing to propose.
+// Players publish signed DailyResults of their grids on a separate topic, and every node aggregates them
+// into a Leaderboard per day. The results are self-reported, as players grade their guesses themselves.
+type Daily struct {
+	genesis     *model.Genesis // with the rules of daily puzzles
+	genesisHash multihash.Multihash
+	dict        *dictionary.Dictionary
+	id          string
+	key         crypto.PrivKey
+	ds          datastore.Datastore
+	store       *Store // keeps the games
+	events      *eventBus
+
+	pubsub *pubsub.PubSub
+	topic  *pubsub.Topic
+	sub    *pubsub.Subscription
+	cancel context.CancelFunc
+
+	lk   sync.Mutex
+	head *model.Header
+	word string
+	mine *dailyEntry
+
+	// resultsLk makes the first result of a peer per day win among concurrent validations
+	resultsLk sync.Mutex
+
+	log func(string)
+}
+
+// dailyEntry is the progress of the local player in the puzzle of a day.
+type dailyEntry struct {
+	Guesses []string
+	// Salt of the commitment in our DailyResult, once we finished the puzzle.
+	Salt []byte `json:",omitempty"`
+}
+
+// NewDaily constructs the daily puzzles of the network with the 'genesis'.
+func NewDaily(genesis *model.Genesis, host core.Host, ds datastore.Batching, pubsub *pubsub.PubSub) *Daily {
+	head, err := genesis.Header()
+	if err != nil {
+		panic(err)
+	}
+	genesisHash, err := head.Hash()
+	if err != nil {
+		panic(err)
+	}
+
+	rules := genesis.Rules
+	if rules.Dictionary == "" {
+		rules.Dictionary = DefaultDailyDictionary
+	}
+	// puzzles last for the day, so they never time out before the next one starts
+	rules.RoundTimeout = 24 * time.Hour
+	dict, err := dictionary.Get(rules.Dictionary)
+	if err != nil {
+		panic(err)
+	}
+
+	ds = namespace.Wrap(ds, datastore.NewKey(genesis.ChainID).ChildString(dailyPeer))
+	return &Daily{
+		genesis: &model.Genesis{
+			ChainID: genesis.ChainID,
+			Word:    genesis.Word,
+			Rules:   rules,
+		},
+		genesisHash: genesisHash,
+		dict:        dict,
+		id:          host.ID().String(),
+		key:         host.Peerstore().PrivKey(host.ID()),
+		ds:          ds,
+		store:       NewStore(ds, head),
+		events:      newEventBus(),
+		pubsub:      pubsub,
+
+		log: func(s string) { fmt.Println(s) },
+	}
+}
+
+func (d *Daily) SetLog(log func(string)) {
+	d.log = log
+}
+
+// dailyTopic is the topic of DailyResults of the network 'chainID'.
+func dailyTopic(chainID string) string {
+	return chainID + "/" + dailyPeer
+}
+
+func (d *Daily) Start(ctx context.Context) (err error) {
+	topic := dailyTopic(d.genesis.ChainID)
+	d.topic, err = d.pubsub.Join(topic)
+	if err != nil {
+		return err
+	}
+
+	err = d.pubsub.RegisterTopicValidator(topic, d.validate)
+	if err != nil {
+		return err
+	}
+
+	// subscribe to receive results, which are processed by the validator
+	d.sub, err = d.topic.Subscribe()
+	if err != nil {
+		return err
+	}
+
+	err = d.roll(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, d.cancel = context.WithCancel(context.Background())
+	go d.drain(ctx)
+	go d.rollDays(ctx)
+	return nil
+}
+
+func (d *Daily) Stop(context.Context) error {
+	d.cancel()
+	d.sub.Cancel()
+	err := d.pubsub.UnregisterTopicValidator(dailyTopic(d.genesis.ChainID))
+	if err != nil {
+		return err
+	}
+	return d.topic.Close()
+}
+
+// ID identifies the local player in results.
+func (d *Daily) ID() string {
+	return d.id
+}
+
+// Genesis returns the Genesis of the network with the rules of daily puzzles.
+func (d *Daily) Genesis() *model.Genesis {
+	return d.genesis
+}
+
+// CurrentRound returns the header committing to the word of the day.
+func (d *Daily) CurrentRound(context.Context) (*model.Header, error) {
+	d.lk.Lock()
+	defer d.lk.Unlock()
+	return d.head, nil
+}
+
+// Events returns a channel receiving Events until the 'ctx' is done.
+func (d *Daily) Events(ctx context.Context) <-chan Event {
+	return d.events.subscribe(ctx)
+}
+
+// SubmitGuess checks the 'guess' of the word of the day and publishes our result, once we are done with it.
+// There is nothing to propose in daily puzzles.
+func (d *Daily) SubmitGuess(ctx context.Context, guess, _ string) error {
+	d.lk.Lock()
+	if d.mine.Salt != nil || len(d.mine.Guesses) >= d.genesis.Rules.MaxAttempts {
+		d.lk.Unlock()
+		return fmt.Errorf("wordle: the puzzle of %s is finished already", model.Day(time.Unix(d.head.Time, 0)))
+	}
+
+	h, err := model.NewHeader(d.head, guess, nil, d.id)
+	if err != nil {
+		d.lk.Unlock()
+		return err
+	}
+	day := model.Day(time.Unix(d.head.Time, 0))
+	solved := model.Verify(h.Guess, d.head.Proposal)
+	d.events.emit(EvtGuessAttempt{Header: h, Successful: solved})
+
+	d.mine.Guesses = append(d.mine.Guesses, h.Solution)
+	var res *model.DailyResult
+	if solved || len(d.mine.Guesses) == d.genesis.Rules.MaxAttempts {
+		res, d.mine.Salt, err = model.NewDailyResult(day, d.word, d.mine.Guesses, d.key)
+		if err != nil {
+			d.lk.Unlock()
+			return err
+		}
+	}
+	err = d.putEntry(ctx, day, d.mine)
+	d.lk.Unlock()
+	if err != nil || res == nil {
+		return err
+	}
+
+	if !solved {
+		d.log(fmt.Sprintf("The word of the day was '%s'", d.word))
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return d.topic.Publish(ctx, data)
+}
+
+// Timeout is not possible in daily puzzles. The next puzzle starts with the next UTC day.
+func (d *Daily) Timeout(context.Context, string) error {
+	return errDailyNoTimeout
+}
+
+// Rounds returns the puzzles of up to 'limit' previous days, starting from yesterday.
+// The best player of the day is the Solver, and the puzzles nobody solved are revealed.
+func (d *Daily) Rounds(ctx context.Context, limit int) ([]*model.Round, error) {
+	d.lk.Lock()
+	head := d.head
+	d.lk.Unlock()
+
+	rounds := make([]*model.Round, 0, limit)
+	for i := 1; i <= limit && head.Height-i > 0; i++ {
+		start := time.Unix(head.Time, 0).UTC().AddDate(0, 0, -i)
+		day := model.Day(start)
+		board, err := d.Leaderboard(ctx, day)
+		if err != nil {
+			return nil, err
+		}
+
+		round := &model.Round{
+			Height:   head.Height - i,
+			Word:     model.DailyWord(d.dict, d.genesisHash, day),
+			Proposer: dailyPeer,
+			Revealed: true,
+			Start:    start,
+			End:      start.AddDate(0, 0, 1),
+		}
+		if len(board) > 0 && board[0].Solved {
+			round.Solver, round.Revealed = board[0].PeerID, false
+		}
+		rounds = append(rounds, round)
+	}
+	return rounds, nil
+}
+
+// Leaderboard returns the results of the 'day' known to us, from the best to the worst, as players reported them.
+// Solving is better than not, fewer attempts are better than more, and ties are ordered by PeerID.
+func (d *Daily) Leaderboard(ctx context.Context, day string) ([]*model.DailyResult, error) {
+	res, err := d.ds.Query(ctx, query.Query{Prefix: resultsKey.ChildString(day).String()})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var board []*model.DailyResult
+	for e := range res.Next() {
+		if e.Error != nil {
+			return nil, e.Error
+		}
+		r := &model.DailyResult{}
+		if err := json.Unmarshal(e.Value, r); err != nil {
+			return nil, err
+		}
+		board = append(board, r)
+	}
+
+	sort.Slice(board, func(i, j int) bool {
+		a, b := board[i], board[j]
+		switch {
+		case a.Solved != b.Solved:
+			return a.Solved
+		case a.Attempts() != b.Attempts():
+			return a.Attempts() < b.Attempts()
+		default:
+			return a.PeerID < b.PeerID
+		}
+	})
+	return board, nil
+}
+
+// SaveGame keeps the 'state' of the local player's game in the puzzle committed by the header with the 'round' hash.
+func (d *Daily) SaveGame(ctx context.Context, round multihash.Multihash, state *GameState) error {
+	return d.store.PutGame(ctx, round, state)
+}
+
+// LoadGame returns the state of the local player's game in the puzzle committed by the header with the 'round' hash.
+func (d *Daily) LoadGame(ctx context.Context, round multihash.Multihash) (*GameState, error) {
+	return d.store.Game(ctx, round)
+}
+
+// rollDays starts the puzzle of every next day, until the 'ctx' is done.
+func (d *Daily) rollDays(ctx context.Context) {
+	ticker := time.NewTicker(dailyRollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			if err := d.roll(ctx, now); err != nil {
+				log.Errorw("starting daily puzzle", "err", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// roll starts the puzzle of the day of 'now', unless it is started already.
+func (d *Daily) roll(ctx context.Context, now time.Time) error {
+	day := model.Day(now)
+	d.lk.Lock()
+	defer d.lk.Unlock()
+	if d.head != nil && model.Day(time.Unix(d.head.Time, 0)) == day {
+		return nil
+	}
+
+	start, err := model.DayStart(day)
+	if err != nil {
+		return err
+	}
+	word := model.DailyWord(d.dict, d.genesisHash, day)
+	prop, err := model.DailyProposal(d.genesisHash, day, word)
+	if err != nil {
+		return err
+	}
+	mine, err := d.entry(ctx, day)
+	if err != nil {
+		return err
+	}
+
+	d.head = &model.Header{
+		// the days since the Unix epoch, counting from one like the chain
+		Height:   int(start.Unix()/(24*60*60)) + 1,
+		Proposal: prop,
+		PeerID:   dailyPeer,
+		Time:     start.Unix(),
+	}
+	d.word, d.mine = word, mine
+	d.events.emit(EvtNewHead{Head: d.head})
+	return nil
+}
+
+// drain consumes results from the topic subscription.
+// They are handled by the validator already, so there is nothing left to do, but to keep receiving them.
+func (d *Daily) drain(ctx context.Context) {
+	for {
+		_, err := d.sub.Next(ctx)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (d *Daily) validate(ctx context.Context, _ peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	res := &model.DailyResult{}
+	err := json.Unmarshal(msg.Data, res)
+	if err != nil {
+		log.Errorw("unmarshalling daily result", "err", err)
+		return pubsub.ValidationReject
+	}
+
+	err = res.Validate(d.genesis.Rules)
+	if err != nil {
+		log.Errorw("invalid daily result", "from", msg.ReceivedFrom, "err", err)
+		return pubsub.ValidationReject
+	}
+
+	// results are accepted during the day and the next one, s.t. players around midnight are not lost
+	start, _ := model.DayStart(res.Day)
+	if now := time.Now(); start.After(now.Add(maxClockDrift)) || now.After(start.AddDate(0, 0, 2)) {
+		log.Warnw("ignoring daily result of another day", "from", msg.ReceivedFrom, "day", res.Day)
+		return pubsub.ValidationIgnore
+	}
+
+	d.resultsLk.Lock()
+	defer d.resultsLk.Unlock()
+	key := resultKey(res.Day, res.PeerID)
+	has, err := d.ds.Has(ctx, key)
+	if err != nil {
+		log.Errorw("checking daily result", "err", err)
+		return pubsub.ValidationIgnore
+	}
+	if has {
+		// the first result of the peer wins, so don't spread others
+		return pubsub.ValidationIgnore
+	}
+
+	err = d.ds.Put(ctx, key, msg.Data)
+	if err != nil {
+		log.Errorw("storing daily result", "err", err)
+		return pubsub.ValidationIgnore
+	}
+
+	d.events.emit(EvtDailyResult{Result: res})
+	return pubsub.ValidationAccept
+}
+
+// entry returns the progress of the local player in the puzzle of the 'day'.
+func (d *Daily) entry(ctx context.Context, day string) (*dailyEntry, error) {
+	data, err := d.ds.Get(ctx, entryKey(day))
+	switch err {
+	case nil:
+	case datastore.ErrNotFound:
+		return &dailyEntry{}, nil
+	default:
+		return nil, err
+	}
+
+	e := &dailyEntry{}
+	return e, json.Unmarshal(data, e)
+}
+
+// putEntry keeps the progress of the local player in the puzzle of the 'day', so that restarts give no more attempts.
+func (d *Daily) putEntry(ctx context.Context, day string, e *dailyEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return d.ds.Put(ctx, entryKey(day), data)
+}
+
+func resultKey(day, peerID string) datastore.Key {
+	return resultsKey.ChildString(day).ChildString(peerID)
+}
+
+func entryKey(day string) datastore.Key {
+	return entriesKey.ChildString(day)
+}
+
+var (
+	resultsKey = datastore.NewKey("results")
+	entriesKey = datastore.NewKey("mine")
+)
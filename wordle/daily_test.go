@@ -0,0 +1,91 @@
+package wordle
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+func TestDaily(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// results are signed, so peers need real keys
+	net := mocknet.New()
+	for i := 0; i < 2; i++ {
+		key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(t, err)
+		_, err = net.AddPeer(key, ma.StringCast(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", 4100+i)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, net.LinkAll())
+
+	dailies := make([]*Daily, 2)
+	dss := make([]datastore.Batching, 2)
+	for i, h := range net.Hosts() {
+		ps, err := pubsub.NewFloodSub(ctx, h, pubsub.WithMessageSignaturePolicy(pubsub.StrictNoSign))
+		require.NoError(t, err)
+
+		dss[i] = sync.MutexWrap(datastore.NewMapDatastore())
+		dailies[i] = NewDaily(DefaultGenesis(), h, dss[i], ps)
+		dailies[i].SetLog(func(string) {})
+		require.NoError(t, dailies[i].Start(ctx))
+		defer dailies[i].Stop(ctx) //nolint:errcheck
+	}
+	require.NoError(t, net.ConnectAllButSelf())
+	// let the floodsub peers learn about each other's topics
+	time.Sleep(time.Millisecond * 100)
+
+	// both nodes derive the same puzzle
+	heads := make([]*model.Header, 2)
+	for i, d := range dailies {
+		var err error
+		heads[i], err = d.CurrentRound(ctx)
+		require.NoError(t, err)
+	}
+	require.Equal(t, heads[0], heads[1])
+	word := dailies[0].word
+	day := model.Day(time.Now())
+
+	// one solves it at once, the other runs out of attempts
+	solver, loser := dailies[0], dailies[1]
+	require.NoError(t, solver.SubmitGuess(ctx, word, ""))
+	require.Error(t, solver.SubmitGuess(ctx, word, ""))
+	wrong := wrongWord(word)
+	for i := 0; i < MaxApptemps; i++ {
+		require.NoError(t, loser.SubmitGuess(ctx, wrong, ""))
+	}
+
+	for _, d := range dailies {
+		require.Eventually(t, func() bool {
+			board, err := d.Leaderboard(ctx, day)
+			return err == nil && len(board) == 2
+		}, time.Second*5, time.Millisecond*50)
+
+		board, err := d.Leaderboard(ctx, day)
+		require.NoError(t, err)
+		assert.Equal(t, solver.ID(), board[0].PeerID)
+		assert.True(t, board[0].Solved)
+		assert.Equal(t, 1, board[0].Attempts())
+		assert.False(t, board[1].Solved)
+	}
+
+	// the attempts survive restarts
+	restarted := NewDaily(DefaultGenesis(), net.Hosts()[1], dss[1], loser.pubsub)
+	require.NoError(t, restarted.roll(ctx, time.Now()))
+	require.Error(t, restarted.SubmitGuess(ctx, word, ""))
+	require.ErrorIs(t, restarted.Timeout(ctx, ""), errDailyNoTimeout)
+}
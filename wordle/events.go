@@ -0,0 +1,129 @@
+package wordle
+
+import (
+	"context"
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+// eventBufferSize is the amount of events buffered for a slow subscriber before they are dropped.
+const eventBufferSize = 64
+
+// Event is a state transition of a GameBackend observed by in-process consumers.
+// It is one of EvtNewHead, EvtGuessAttempt, EvtPeerJoined, EvtSyncProgress, EvtDailyResult, EvtLaneHead,
+// EvtChallenge or EvtChallengeResult.
+type Event interface {
+	event()
+}
+
+// EvtNewHead is emitted whenever the canonical head changes.
+type EvtNewHead struct {
+	Head *model.Header
+}
+
+// EvtGuessAttempt is emitted for every guess on the current head, including ours.
+type EvtGuessAttempt struct {
+	Header *model.Header
+	// Successful is true if the guess solved the word and its header became the new head.
+	Successful bool
+}
+
+// EvtPeerJoined is emitted when a peer is verified to play from the same genesis.
+type EvtPeerJoined struct {
+	Peer peer.ID
+}
+
+// EvtSyncProgress is emitted while syncing headers from peers.
+type EvtSyncProgress struct {
+	Height, Target int
+}
+
+// EvtDailyResult is emitted for every new result of the daily puzzle, including ours.
+type EvtDailyResult struct {
+	Result *model.DailyResult
+}
+
+// EvtLaneHead is emitted whenever the head of a lane, other than the selected one, changes.
+type EvtLaneHead struct {
+	Lane int
+	Head *model.Header
+}
+
+// EvtChallenge is emitted for every challenge received from another player.
+type EvtChallenge struct {
+	Challenge *model.Challenge
+}
+
+// EvtChallengeResult is emitted when a challenge is finished, by us, by the player we challenged,
+// or by anyone publishing the result as a record.
+type EvtChallengeResult struct {
+	Result *model.ChallengeResult
+}
+
+// EvtTeamJoined is emitted when another member of our team is seen for the first time.
+type EvtTeamJoined struct {
+	PeerID string
+}
+
+// EvtTeamGuess is emitted for every guess of another member of our team.
+type EvtTeamGuess struct {
+	PeerID string
+	// Round is the hash of the header starting the round guessed in.
+	Round multihash.Multihash
+	Guess string
+}
+
+func (EvtNewHead) event()         {}
+func (EvtGuessAttempt) event()    {}
+func (EvtPeerJoined) event()      {}
+func (EvtSyncProgress) event()    {}
+func (EvtDailyResult) event()     {}
+func (EvtLaneHead) event()        {}
+func (EvtChallenge) event()       {}
+func (EvtChallengeResult) event() {}
+func (EvtTeamJoined) event()      {}
+func (EvtTeamGuess) event()       {}
+
+// eventBus fans out events to all the subscribers.
+// Events are dropped for subscribers not keeping up, so emitters never wait for them.
+type eventBus struct {
+	lk   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: make(map[chan Event]struct{})}
+}
+
+// subscribe returns a channel receiving events until the 'ctx' is done.
+func (b *eventBus) subscribe(ctx context.Context) <-chan Event {
+	sub := make(chan Event, eventBufferSize)
+	b.lk.Lock()
+	b.subs[sub] = struct{}{}
+	b.lk.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.lk.Lock()
+		delete(b.subs, sub)
+		b.lk.Unlock()
+		close(sub)
+	}()
+	return sub
+}
+
+func (b *eventBus) emit(evt Event) {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	for sub := range b.subs {
+		select {
+		case sub <- evt:
+		default:
+			log.Warnw("dropping event for a slow subscriber", "event", evt)
+		}
+	}
+}
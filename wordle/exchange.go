@@ -0,0 +1,104 @@
+package wordle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	msngr "github.com/celestiaorg/go-libp2p-messenger"
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+// requestTimeout limits the time we wait for a peer to respond.
+var requestTimeout = time.Second * 10
+
+var errNoHeader = errors.New("wordle: peer does not have the header")
+
+// exchange is the client side of the header exchange protocol.
+// It correlates responses with requests by IDs, so requests to multiple peers run concurrently
+// and a slow peer only delays its own request.
+type exchange struct {
+	reqs, resps *msngr.Messenger
+
+	lastID  uint64
+	lk      sync.Mutex
+	pending map[uint64]*pendingRequest
+}
+
+// pendingRequest is a request waiting for a response from the peer it was sent to.
+type pendingRequest struct {
+	peer peer.ID
+	resp chan *model.Header
+}
+
+func newExchange(reqs, resps *msngr.Messenger) *exchange {
+	return &exchange{
+		reqs:    reqs,
+		resps:   resps,
+		pending: make(map[uint64]*pendingRequest),
+	}
+}
+
+// run delivers responses to the pending requests until the 'ctx' is done.
+func (e *exchange) run(ctx context.Context) {
+	for {
+		msg, from, err := e.resps.Receive(ctx)
+		if err != nil {
+			return
+		}
+		resp := msg.(*HeaderResponse)
+
+		e.lk.Lock()
+		req, ok := e.pending[resp.ID]
+		if ok && req.peer == from {
+			delete(e.pending, resp.ID)
+		}
+		e.lk.Unlock()
+
+		switch {
+		case !ok:
+			log.Debugw("response to an unknown request", "peer", from, "id", resp.ID)
+		case req.peer != from:
+			log.Warnw("response from a peer the request was not sent to", "peer", from, "id", resp.ID)
+		default:
+			req.resp <- resp.Header
+		}
+	}
+}
+
+// request asks the peer 'p' for the header at the given 'height', where 0 means the head,
+// and waits for the response at most requestTimeout.
+func (e *exchange) request(ctx context.Context, p peer.ID, height int) (*model.Header, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	id := atomic.AddUint64(&e.lastID, 1)
+	req := &pendingRequest{peer: p, resp: make(chan *model.Header, 1)}
+	e.lk.Lock()
+	e.pending[id] = req
+	e.lk.Unlock()
+	defer func() {
+		e.lk.Lock()
+		delete(e.pending, id)
+		e.lk.Unlock()
+	}()
+
+	err := <-e.reqs.Send(ctx, &HeaderRequest{ID: id, Height: height}, p)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case h := <-req.resp:
+		if h == nil {
+			return nil, errNoHeader
+		}
+		return h, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
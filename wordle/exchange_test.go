@@ -0,0 +1,74 @@
+package wordle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	msngr "github.com/celestiaorg/go-libp2p-messenger"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+func TestExchange(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	net, err := mocknet.FullMeshConnected(3)
+	require.NoError(t, err)
+
+	messengers := func(i int) (*msngr.Messenger, *msngr.Messenger) {
+		reqs, err := msngr.New(net.Hosts()[i],
+			msngr.WithProtocols("/test/req"), msngr.WithMessageType(&HeaderRequest{}))
+		require.NoError(t, err)
+		resps, err := msngr.New(net.Hosts()[i],
+			msngr.WithProtocols("/test/resp"), msngr.WithMessageType(&HeaderResponse{}))
+		require.NoError(t, err)
+		return reqs, resps
+	}
+
+	ex := newExchange(messengers(0))
+	go ex.run(ctx)
+	srvReqs, srvResps := messengers(1)
+	messengers(2) // a peer which never responds
+
+	// the server responds to two requests in the reverse order
+	go func() {
+		reqs := make([]*HeaderRequest, 2)
+		for i := range reqs {
+			msg, _, err := srvReqs.Receive(ctx)
+			if err != nil {
+				return
+			}
+			reqs[i] = msg.(*HeaderRequest)
+		}
+
+		for i := len(reqs) - 1; i >= 0; i-- {
+			resp := &HeaderResponse{ID: reqs[i].ID, Header: &model.Header{Height: reqs[i].Height}}
+			<-srvResps.Send(ctx, resp, net.Hosts()[0].ID())
+		}
+	}()
+
+	// each request gets its own response
+	done := make(chan struct{}, 2)
+	for _, height := range []int{2, 3} {
+		go func(height int) {
+			defer func() { done <- struct{}{} }()
+			h, err := ex.request(ctx, net.Hosts()[1].ID(), height)
+			if assert.NoError(t, err) {
+				assert.Equal(t, height, h.Height)
+			}
+		}(height)
+	}
+	<-done
+	<-done
+
+	requestTimeout = time.Millisecond * 100
+	defer func() { requestTimeout = time.Second * 10 }()
+	_, err = ex.request(ctx, net.Hosts()[2].ID(), 2)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, ex.pending)
+}
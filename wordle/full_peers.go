@@ -0,0 +1,98 @@
+package wordle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	idiscovery "github.com/libp2p/go-libp2p-core/discovery"
+	"github.com/libp2p/go-libp2p-core/peer"
+	discovery "github.com/libp2p/go-libp2p-discovery"
+)
+
+// fullNodesNamespace returns the discovery namespace Full Nodes of the 'chainID' advertise themselves under.
+func fullNodesNamespace(chainID string) string {
+	return chainID + "/full"
+}
+
+// fullPeersDiscoveryInterval defines how often we look for new Full Nodes.
+var fullPeersDiscoveryInterval = time.Minute
+
+// Advertise persistently advertises the node as a Full Node of the 'chainID' over the given Discovery,
+// until the given context 'ctx' is canceled.
+func Advertise(ctx context.Context, disc idiscovery.Discovery, chainID string) {
+	discovery.Advertise(ctx, disc, fullNodesNamespace(chainID))
+}
+
+// discoverFullPeers periodically looks for advertised Full Nodes and connects to them.
+func (s *Service) discoverFullPeers(ctx context.Context) {
+	t := time.NewTicker(fullPeersDiscoveryInterval)
+	defer t.Stop()
+	ns := fullNodesNamespace(s.genesis.ChainID)
+	for {
+		peers, err := s.disc.FindPeers(ctx, ns)
+		if err != nil {
+			log.Errorw("finding full peers", "err", err)
+		} else {
+			for p := range peers {
+				if p.ID == s.host.ID() || s.fullPeers.Has(p.ID) {
+					continue
+				}
+
+				err = s.host.Connect(ctx, p)
+				if err != nil {
+					log.Debugw("connecting to full peer", "peer", p.ID, "err", err)
+					continue
+				}
+
+				s.host.ConnManager().Protect(p.ID, ns)
+				s.fullPeers.Add(p.ID)
+				log.Debugw("discovered full peer", "peer", p.ID)
+			}
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// connectedFullPeers returns Full Nodes we are currently able to speak the protocol with.
+func (s *Service) connectedFullPeers() []peer.ID {
+	peers := s.peers()
+	full := make([]peer.ID, 0, len(peers))
+	for _, p := range peers {
+		if s.fullPeers.Has(p) {
+			full = append(full, p)
+		}
+	}
+	return full
+}
+
+// peerSet is a thread-safe set of peers.
+type peerSet struct {
+	lk  sync.RWMutex
+	set map[peer.ID]struct{}
+}
+
+func newPeerSet() *peerSet {
+	return &peerSet{set: make(map[peer.ID]struct{})}
+}
+
+// Add adds the peer 'p' to the set and reports whether it was not there before.
+func (ps *peerSet) Add(p peer.ID) bool {
+	ps.lk.Lock()
+	defer ps.lk.Unlock()
+	_, ok := ps.set[p]
+	ps.set[p] = struct{}{}
+	return !ok
+}
+
+func (ps *peerSet) Has(p peer.ID) bool {
+	ps.lk.RLock()
+	defer ps.lk.RUnlock()
+	_, ok := ps.set[p]
+	return ok
+}
@@ -0,0 +1,76 @@
+package wordle
+
+import (
+	"fmt"
+	"time"
+)
+
+// State is a state of the local player in a round.
+type State uint8
+
+const (
+	// StateProposing is waiting for the word we propose next, if we solve the round.
+	StateProposing State = iota
+	// StateGuessing is guessing the word of the round.
+	StateGuessing
+	// StateSolved is having solved the round, until the network accepts the solution.
+	StateSolved
+	// StateOutOfAttempts is having no more attempts to solve the round.
+	StateOutOfAttempts
+	// StateSpectating is watching others guess the word we proposed.
+	StateSpectating
+	// StateRoundTimedOut is waiting for the word to reveal or to skip the timed out round with.
+	StateRoundTimedOut
+)
+
+func (s State) String() string {
+	switch s {
+	case StateProposing:
+		return "Proposing"
+	case StateGuessing:
+		return "Guessing"
+	case StateSolved:
+		return "Solved"
+	case StateOutOfAttempts:
+		return "OutOfAttempts"
+	case StateSpectating:
+		return "Spectating"
+	case StateRoundTimedOut:
+		return "RoundTimedOut"
+	default:
+		return fmt.Sprintf("State(%d)", s)
+	}
+}
+
+// transitions lists the states every State can go to.
+var transitions = map[State][]State{
+	StateProposing:     {StateGuessing},
+	StateGuessing:      {StateGuessing, StateSolved, StateOutOfAttempts},
+	StateSolved:        {StateSolved, StateRoundTimedOut}, // retrying to publish the solution
+	StateOutOfAttempts: {StateRoundTimedOut},
+	StateSpectating:    {StateRoundTimedOut},
+	StateRoundTimedOut: {StateRoundTimedOut}, // retrying to reveal or skip
+}
+
+// CanGo reports whether the State can go to the State 'to'.
+func (s State) CanGo(to State) bool {
+	for _, st := range transitions[s] {
+		if st == to {
+			return true
+		}
+	}
+	return false
+}
+
+// GameEvent is an entry of the game's log.
+type GameEvent struct {
+	Time time.Time
+	// From and To are the states before and after the event.
+	From, To State
+	// Input is the word causing the event.
+	Input string
+}
+
+func (e GameEvent) String() string {
+	return fmt.Sprintf("%s %s -> %s '%s'", e.Time.Format(time.Kitchen), e.From, e.To, e.Input)
+}
@@ -0,0 +1,136 @@
+package wordle
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/event"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+)
+
+// handshakeTimeout limits the time of a single handshake.
+var handshakeTimeout = time.Second * 10
+
+// maxHashSize bounds the amount of bytes we read from a handshake stream.
+const maxHashSize = 128
+
+// handshakeID is the protocol ID for genesis handshakes.
+func (s *Service) handshakeID() protocol.ID {
+	return s.protoID + "/handshake"
+}
+
+// startHandshakes makes the Service exchange genesis hashes with every peer speaking the protocol,
+// so that peers from a different genesis are refused.
+func (s *Service) startHandshakes(ctx context.Context) error {
+	s.host.SetStreamHandler(s.handshakeID(), s.handleHandshake)
+
+	sub, err := s.host.EventBus().Subscribe(&event.EvtPeerIdentificationCompleted{})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer sub.Close()
+		// handshake peers we are already connected to
+		for _, p := range s.host.Network().Peers() {
+			go s.handshake(ctx, p)
+		}
+
+		for {
+			select {
+			case evt, ok := <-sub.Out():
+				if !ok {
+					return
+				}
+				go s.handshake(ctx, evt.(event.EvtPeerIdentificationCompleted).Peer)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// handshake sends our genesis hash to the peer 'p' and verifies the one it responds with.
+func (s *Service) handshake(ctx context.Context, p peer.ID) {
+	protos, err := s.host.Peerstore().SupportsProtocols(p, string(s.handshakeID()))
+	if err != nil || len(protos) == 0 {
+		return // not a Wordle peer
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+
+	stream, err := s.host.NewStream(ctx, p, s.handshakeID())
+	if err != nil {
+		log.Debugw("opening handshake stream", "peer", p, "err", err)
+		return
+	}
+	defer stream.Close()
+
+	deadline, _ := ctx.Deadline()
+	_ = stream.SetDeadline(deadline)
+
+	_, err = stream.Write(s.genesisHash)
+	if err != nil {
+		log.Debugw("sending genesis hash", "peer", p, "err", err)
+		return
+	}
+
+	err = stream.CloseWrite()
+	if err != nil {
+		log.Debugw("closing handshake stream", "peer", p, "err", err)
+		return
+	}
+
+	hash, err := io.ReadAll(io.LimitReader(stream, maxHashSize))
+	if err != nil {
+		log.Debugw("receiving genesis hash", "peer", p, "err", err)
+		return
+	}
+
+	s.verifyGenesis(p, hash)
+}
+
+// handleHandshake responds with our genesis hash to the hash received from a peer.
+func (s *Service) handleHandshake(stream network.Stream) {
+	defer stream.Close()
+	_ = stream.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	p := stream.Conn().RemotePeer()
+	hash, err := io.ReadAll(io.LimitReader(stream, maxHashSize))
+	if err != nil {
+		log.Debugw("receiving genesis hash", "peer", p, "err", err)
+		return
+	}
+
+	_, err = stream.Write(s.genesisHash)
+	if err != nil {
+		log.Debugw("sending genesis hash", "peer", p, "err", err)
+		return
+	}
+
+	s.verifyGenesis(p, hash)
+}
+
+// verifyGenesis checks that the peer 'p' plays from the same genesis.
+// Peers from a different genesis are disconnected and their messages are ignored.
+func (s *Service) verifyGenesis(p peer.ID, hash []byte) {
+	if bytes.Equal(hash, s.genesisHash) {
+		if s.genesisPeers.Add(p) {
+			s.events.emit(EvtPeerJoined{Peer: p})
+		}
+		return
+	}
+
+	log.Warnw("refusing peer from a different genesis", "peer", p)
+	s.pubsub.BlacklistPeer(p)
+	err := s.host.Network().ClosePeer(p)
+	if err != nil {
+		log.Debugw("closing peer", "peer", p, "err", err)
+	}
+}
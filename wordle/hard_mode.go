@@ -0,0 +1,49 @@
+package wordle
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+// attemptLog keeps the guesses every player published in the current hard round, so that a player can not hide
+// earlier attempts, and the hints they revealed, from the attempts of the later guesses.
+type attemptLog struct {
+	lk sync.Mutex
+	// height of the head starting the current round
+	height int
+	seen   map[string][]string // by PeerID
+}
+
+func newAttemptLog() *attemptLog {
+	return &attemptLog{seen: make(map[string][]string)}
+}
+
+// add checks that the attempts of the Header 'h' guessing in the round started by the 'head', which 'h' is validated
+// to follow, include all the guesses its player published in the round before, and logs its guess.
+func (l *attemptLog) add(head, h *model.Header) error {
+	l.lk.Lock()
+	defer l.lk.Unlock()
+	switch {
+	case head.Height > l.height:
+		// the chain moved on, and only the current round matters
+		l.height, l.seen = head.Height, make(map[string][]string)
+	case head.Height < l.height:
+		// validated against a head the chain has moved on from
+		return nil
+	}
+
+	seen := l.seen[h.PeerID]
+	if len(h.Attempts) < len(seen) {
+		return fmt.Errorf("wordle: %d attempts, while %d were published", len(h.Attempts), len(seen))
+	}
+	for i, a := range seen {
+		if h.Attempts[i] != a {
+			return fmt.Errorf("wordle: attempt %d is '%s', while '%s' was published", i+1, h.Attempts[i], a)
+		}
+	}
+
+	l.seen[h.PeerID] = append(append([]string(nil), h.Attempts...), h.Solution)
+	return nil
+}
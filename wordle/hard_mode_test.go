@@ -0,0 +1,37 @@
+package wordle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+func TestAttemptLog(t *testing.T) {
+	head := &model.Header{Height: 2}
+	guess := func(peerID, solution string, attempts ...string) *model.Header {
+		return &model.Header{Height: 3, PeerID: peerID, Solution: solution, Attempts: attempts}
+	}
+
+	l := newAttemptLog()
+	require.NoError(t, l.add(head, guess("alice", "wrong")))
+	require.NoError(t, l.add(head, guess("alice", "worry", "wrong")))
+	require.NoError(t, l.add(head, guess("bob", "hello")))
+
+	// published guesses can not be hidden or changed
+	require.Error(t, l.add(head, guess("alice", "world")))
+	require.Error(t, l.add(head, guess("alice", "world", "wrong")))
+	require.Error(t, l.add(head, guess("alice", "world", "wrong", "wordy")))
+	require.NoError(t, l.add(head, guess("alice", "world", "wrong", "worry")))
+
+	// attempts we missed are fine
+	require.NoError(t, l.add(head, guess("carol", "world", "wrong", "worry")))
+
+	// guesses validated against an older head change nothing
+	require.NoError(t, l.add(&model.Header{Height: 1}, guess("alice", "hello")))
+	require.Error(t, l.add(head, guess("alice", "hello")))
+
+	// every round starts over
+	require.NoError(t, l.add(&model.Header{Height: 3}, guess("alice", "hello")))
+}
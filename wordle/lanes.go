@@ -0,0 +1,187 @@
+package wordle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+// Lanes is a GameBackend over the lanes of the network, i.e. sub-chains played in parallel by Services of their own.
+// The local player attacks the puzzle of the selected lane, while the others keep going on their own.
+type Lanes struct {
+	lanes  []*Service
+	events *eventBus
+	cancel context.CancelFunc
+
+	lk       sync.RWMutex
+	selected int
+}
+
+// NewLanes plays the 'lanes' of the network, starting with the main chain.
+func NewLanes(lanes ...*Service) *Lanes {
+	if len(lanes) == 0 {
+		panic("wordle: no lanes")
+	}
+	return &Lanes{
+		lanes:  lanes,
+		events: newEventBus(),
+	}
+}
+
+func (l *Lanes) Start(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+	for i, lane := range l.lanes {
+		go l.forward(ctx, i, lane.Events(ctx))
+	}
+	return nil
+}
+
+func (l *Lanes) Stop(context.Context) error {
+	l.cancel()
+	return nil
+}
+
+func (l *Lanes) SetLog(log func(string)) {
+	for _, lane := range l.lanes {
+		lane.SetLog(log)
+	}
+}
+
+// Len returns the amount of lanes.
+func (l *Lanes) Len() int {
+	return len(l.lanes)
+}
+
+// Lane returns the Service of the lane 'i'.
+func (l *Lanes) Lane(i int) *Service {
+	return l.lanes[i]
+}
+
+// Selected returns the index of the selected lane.
+func (l *Lanes) Selected() int {
+	l.lk.RLock()
+	defer l.lk.RUnlock()
+	return l.selected
+}
+
+// Select makes the lane 'i' the one played, starting a new round of its current word for the subscribers.
+func (l *Lanes) Select(i int) error {
+	if i < 0 || i >= len(l.lanes) {
+		return fmt.Errorf("wordle: no lane %d, there are %d", i, len(l.lanes))
+	}
+
+	l.lk.Lock()
+	defer l.lk.Unlock()
+	l.selected = i
+	l.events.emit(EvtNewHead{Head: l.lanes[i].chain.Head()})
+	return nil
+}
+
+// WordLen returns the length in letters of the words we propose. Zero means any length.
+func (l *Lanes) WordLen() int {
+	return l.lanes[0].WordLen()
+}
+
+// SetWordLen makes us propose words of 'n' letters in every lane, or of any length, if 'n' is zero.
+func (l *Lanes) SetWordLen(n int) error {
+	for _, lane := range l.lanes {
+		if err := lane.SetWordLen(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lane returns the Service of the selected lane.
+func (l *Lanes) lane() *Service {
+	return l.lanes[l.Selected()]
+}
+
+// ID identifies the local player in headers.
+func (l *Lanes) ID() string {
+	return l.lanes[0].ID()
+}
+
+// Genesis returns the Genesis of the selected lane.
+func (l *Lanes) Genesis() *model.Genesis {
+	return l.lane().Genesis()
+}
+
+// CurrentRound returns the header starting the current round of the selected lane.
+func (l *Lanes) CurrentRound(ctx context.Context) (*model.Header, error) {
+	return l.lane().CurrentRound(ctx)
+}
+
+// Events returns a channel receiving Events of the selected lane and EvtLaneHeads of the others
+// until the 'ctx' is done.
+func (l *Lanes) Events(ctx context.Context) <-chan Event {
+	return l.events.subscribe(ctx)
+}
+
+// SubmitGuess publishes the 'guess' of the current word of the selected lane, proposing the 'proposal' next.
+func (l *Lanes) SubmitGuess(ctx context.Context, guess, proposal string) error {
+	return l.lane().SubmitGuess(ctx, guess, proposal)
+}
+
+// Timeout reveals or skips the current word of the selected lane, proposing the 'proposal' next.
+func (l *Lanes) Timeout(ctx context.Context, proposal string) error {
+	return l.lane().Timeout(ctx, proposal)
+}
+
+// Rounds returns up to 'limit' latest finished rounds of the selected lane, starting from the latest one.
+func (l *Lanes) Rounds(ctx context.Context, limit int) ([]*model.Round, error) {
+	return l.lane().Rounds(ctx, limit)
+}
+
+// SaveGame keeps the 'state' of the local player's game in the round started by the header with the 'round' hash.
+// Every lane keeps the games of its rounds, as its guesses carry the attempts of hard rounds from them.
+func (l *Lanes) SaveGame(ctx context.Context, round multihash.Multihash, state *GameState) error {
+	return l.laneOf(round).SaveGame(ctx, round, state)
+}
+
+// LoadGame returns the state of the local player's game in the round started by the header with the 'round' hash.
+func (l *Lanes) LoadGame(ctx context.Context, round multihash.Multihash) (*GameState, error) {
+	return l.laneOf(round).LoadGame(ctx, round)
+}
+
+// laneOf returns the Service of the lane whose current round is started by the header with the 'round' hash,
+// or of the selected lane, if there is none, e.g. as the round is over.
+func (l *Lanes) laneOf(round multihash.Multihash) *Service {
+	for _, lane := range l.lanes {
+		hash, err := lane.chain.Head().Hash()
+		if err == nil && bytes.Equal(hash, round) {
+			return lane
+		}
+	}
+	return l.lane()
+}
+
+// forward re-emits the 'events' of the lane 'i', as they are, if the lane is selected,
+// and as EvtLaneHead, if it is not.
+func (l *Lanes) forward(ctx context.Context, i int, events <-chan Event) {
+	for {
+		select {
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+
+			// the lock orders the events against selecting lanes
+			l.lk.RLock()
+			if i == l.selected {
+				l.events.emit(evt)
+			} else if evt, ok := evt.(EvtNewHead); ok {
+				l.events.emit(EvtLaneHead{Lane: i, Head: evt.Head})
+			}
+			l.lk.RUnlock()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package wordle
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+func TestLanes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	rules := DefaultGenesis().Rules
+	rules.Lanes = 2
+	g, err := model.NewOpenGenesis("lanes", rules)
+	require.NoError(t, err)
+
+	nodes := newTestLanes(ctx, t, g, pubsub.WithMessageSignaturePolicy(pubsub.StrictNoSign))
+	player, other := nodes[0], nodes[1]
+	playerEvts, otherEvts := player.Events(ctx), other.Events(ctx)
+
+	// selecting a lane starts a round of its word
+	require.Error(t, player.Select(2))
+	require.NoError(t, player.Select(1))
+	head := nextEvent(ctx, t, playerEvts).(EvtNewHead).Head
+	assert.Equal(t, player.Lane(1).chain.Head(), head)
+	assert.Equal(t, g.Lanes()[1], player.Genesis())
+
+	// solving the first word starts the lane
+	require.NoError(t, player.SubmitGuess(ctx, g.ChainID, "world"))
+	require.Eventually(t, func() bool {
+		return other.Lane(1).chain.Head().Height == 2
+	}, time.Second*5, time.Millisecond*50)
+	assert.Equal(t, 1, other.Lane(0).chain.Head().Height)
+
+	// others are told about the lane, while they keep playing their own
+	for {
+		evt, ok := nextEvent(ctx, t, otherEvts).(EvtLaneHead)
+		if ok {
+			assert.Equal(t, 1, evt.Lane)
+			assert.Equal(t, player.ID(), evt.Head.PeerID)
+			break
+		}
+	}
+	for {
+		evt, ok := nextEvent(ctx, t, playerEvts).(EvtNewHead)
+		if ok {
+			assert.Equal(t, 2, evt.Head.Height)
+			break
+		}
+	}
+	head, err = other.CurrentRound(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, head.Height)
+}
+
+func TestLanes_HardMode(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	rules := DefaultGenesis().Rules
+	rules.Lanes = 2
+	rules.HardMode = true
+	g, err := model.NewOpenGenesis("lanes", rules)
+	require.NoError(t, err)
+
+	// guesses are bound to their players only by signed messages
+	nodes := newTestLanes(ctx, t, g)
+	player, other := nodes[0], nodes[1]
+	require.NoError(t, player.Select(1))
+
+	head, err := player.CurrentRound(ctx)
+	require.NoError(t, err)
+	round, err := head.Hash()
+	require.NoError(t, err)
+
+	// the game is saved with every guess before it is submitted, as WordGame does
+	state := &GameState{State: StateGuessing}
+	for _, guess := range []string{"lance", g.ChainID} {
+		state.AttemptedWords = append(state.AttemptedWords, guess)
+		require.NoError(t, player.SaveGame(ctx, round, state))
+		require.NoError(t, player.SubmitGuess(ctx, guess, "world"))
+	}
+
+	require.Eventually(t, func() bool {
+		return other.Lane(1).chain.Head().Height == 2
+	}, time.Second*5, time.Millisecond*50)
+	assert.Equal(t, []string{"lance"}, other.Lane(1).chain.Head().Attempts)
+
+	loaded, err := player.LoadGame(ctx, round)
+	require.NoError(t, err)
+	assert.Equal(t, state, loaded)
+}
+
+// newTestLanes starts two connected nodes playing the lanes of the Genesis 'g'.
+func newTestLanes(ctx context.Context, t *testing.T, g *model.Genesis, opts ...pubsub.Option) []*Lanes {
+	// signed messages need real keys
+	net := mocknet.New()
+	for i := 0; i < 2; i++ {
+		key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(t, err)
+		_, err = net.AddPeer(key, ma.StringCast(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", 4400+i)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, net.LinkAll())
+
+	nodes := make([]*Lanes, 2)
+	for i, h := range net.Hosts() {
+		ds := sync.MutexWrap(datastore.NewMapDatastore())
+		ps, err := pubsub.NewFloodSub(ctx, h, opts...)
+		require.NoError(t, err)
+
+		var servs []*Service
+		for _, lane := range g.Lanes() {
+			serv := NewService(DefaultConfig(), lane, h, ds, ps, nil)
+			serv.SetLog(func(string) {})
+			require.NoError(t, serv.Start(ctx))
+			servs = append(servs, serv)
+		}
+		nodes[i] = NewLanes(servs...)
+		require.NoError(t, nodes[i].Start(ctx))
+		t.Cleanup(func() { nodes[i].Stop(ctx) }) //nolint:errcheck
+	}
+
+	require.NoError(t, net.ConnectAllButSelf())
+	for _, l := range nodes {
+		for i := 0; i < l.Len(); i++ {
+			select {
+			case <-l.Lane(i).bootsrapped:
+			case <-ctx.Done():
+				t.Fatal(ctx.Err())
+			}
+		}
+	}
+	return nodes
+}
+
+func nextEvent(ctx context.Context, t *testing.T, events <-chan Event) Event {
+	select {
+	case evt := <-events:
+		return evt
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+package wordle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	rcmgr "github.com/libp2p/go-libp2p-resource-manager"
+)
+
+// maxTrackedPeers bounds the amount of peers rateLimiter keeps buckets for before forgetting idle ones.
+const maxTrackedPeers = 1024
+
+// rateLimiter limits the rate of requests per peer with token buckets.
+type rateLimiter struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	lk      sync.Mutex
+	buckets map[peer.ID]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[peer.ID]*bucket),
+	}
+}
+
+// Allow takes a token from the bucket of the peer 'p' and reports whether there was one.
+func (rl *rateLimiter) Allow(p peer.ID) bool {
+	rl.lk.Lock()
+	defer rl.lk.Unlock()
+
+	now := time.Now()
+	b, ok := rl.buckets[p]
+	if !ok {
+		if len(rl.buckets) >= maxTrackedPeers {
+			rl.forgetIdle(now)
+		}
+		b = &bucket{tokens: rl.burst, last: now}
+		rl.buckets[p] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * rl.rate
+	if b.tokens > rl.burst {
+		b.tokens = rl.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// forgetIdle removes buckets which are refilled already, as they are the same as new ones.
+func (rl *rateLimiter) forgetIdle(now time.Time) {
+	for p, b := range rl.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*rl.rate >= rl.burst {
+			delete(rl.buckets, p)
+		}
+	}
+}
+
+// SetResourceLimits adds limits for the header exchange and challenge protocols of the network 'chainID'
+// to the 'limiter' of the libp2p resource manager, so that the protocols cannot be used to exhaust the node.
+// Every peer keeps a single long-lived stream per protocol and direction.
+func SetResourceLimits(limiter *rcmgr.BasicLimiter, chainID string) {
+	if limiter.ProtocolLimits == nil {
+		limiter.ProtocolLimits = make(map[protocol.ID]rcmgr.Limit)
+	}
+	if limiter.ProtocolPeerLimits == nil {
+		limiter.ProtocolPeerLimits = make(map[protocol.ID]rcmgr.Limit)
+	}
+
+	protoID := protocolID(chainID)
+	protos := []protocol.ID{protoID + "/req", protoID + "/resp", protoID + "/handshake", protoID + "/challenge"}
+	for _, proto := range protos {
+		limiter.ProtocolLimits[proto] = &rcmgr.StaticLimit{
+			BaseLimit: rcmgr.BaseLimit{
+				StreamsInbound:  512,
+				StreamsOutbound: 512,
+				Streams:         1024,
+			},
+			Memory: 16 << 20,
+		}
+		limiter.ProtocolPeerLimits[proto] = &rcmgr.StaticLimit{
+			BaseLimit: rcmgr.BaseLimit{
+				StreamsInbound:  2,
+				StreamsOutbound: 2,
+				Streams:         4,
+			},
+			Memory: 1 << 20,
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package wordle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	rl := newRateLimiter(100, 3)
+	a, b := peer.ID("a"), peer.ID("b")
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, rl.Allow(a))
+	}
+	assert.False(t, rl.Allow(a), "burst is exhausted")
+	assert.True(t, rl.Allow(b), "peers are limited separately")
+
+	time.Sleep(time.Millisecond * 20) // refill at least one token
+	assert.True(t, rl.Allow(a))
+
+	rl.forgetIdle(time.Now().Add(time.Second))
+	assert.Empty(t, rl.buckets)
+}
@@ -0,0 +1,166 @@
+package wordle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+// MemoryBackend is a GameBackend keeping the chain in memory without any networking,
+// e.g. for offline practice, tests and bots. Other players take part in the game with Play.
+type MemoryBackend struct {
+	genesis *model.Genesis
+	store   *Store
+	chain   *chain
+	player  *localPlayer
+	events  *eventBus
+
+	log func(string)
+}
+
+// NewMemoryBackend starts the game of the 'genesis' for the local player with the 'key',
+// which runs until the 'ctx' is done.
+func NewMemoryBackend(ctx context.Context, genesis *model.Genesis, key crypto.PrivKey) (*MemoryBackend, error) {
+	head, err := genesis.Header()
+	if err != nil {
+		return nil, err
+	}
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	store := NewStore(sync.MutexWrap(datastore.NewMapDatastore()), head)
+	events := newEventBus()
+	chain := newChain(store, genesis.Rules, events)
+	err = chain.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemoryBackend{
+		genesis: genesis,
+		store:   store,
+		chain:   chain,
+		player: &localPlayer{
+			id:      id.String(),
+			key:     key,
+			rules:   genesis.Rules,
+			saltLen: model.DefaultSaltLength,
+			store:   store,
+			chain:   chain,
+		},
+		events: events,
+		log:    func(string) {},
+	}, nil
+}
+
+func (b *MemoryBackend) SetLog(log func(string)) {
+	b.log = log
+}
+
+// ID identifies the local player in headers.
+func (b *MemoryBackend) ID() string {
+	return b.player.id
+}
+
+// Genesis returns the Genesis of the game.
+func (b *MemoryBackend) Genesis() *model.Genesis {
+	return b.genesis
+}
+
+// WordLen returns the length in letters of the words we propose. Zero means any length.
+func (b *MemoryBackend) WordLen() int {
+	return b.player.wordLength()
+}
+
+// SetWordLen makes us propose words of 'n' letters, or of any length, if 'n' is zero.
+func (b *MemoryBackend) SetWordLen(n int) error {
+	return b.player.setWordLen(n)
+}
+
+// CurrentRound returns the header starting the current round.
+func (b *MemoryBackend) CurrentRound(context.Context) (*model.Header, error) {
+	return b.chain.Head(), nil
+}
+
+// Events returns a channel receiving Events until the 'ctx' is done.
+func (b *MemoryBackend) Events(ctx context.Context) <-chan Event {
+	return b.events.subscribe(ctx)
+}
+
+// SubmitGuess plays the 'guess' of the current word, proposing the 'proposal' next.
+func (b *MemoryBackend) SubmitGuess(ctx context.Context, guess, proposal string) error {
+	head, err := b.player.guess(ctx, guess, proposal)
+	if err != nil {
+		return err
+	}
+	return b.Play(ctx, head)
+}
+
+// Timeout reveals our current word or skips someone's, once the round timed out, proposing the 'proposal' next.
+func (b *MemoryBackend) Timeout(ctx context.Context, proposal string) error {
+	head, err := b.player.timeout(ctx, proposal)
+	if err != nil {
+		return err
+	}
+	return b.Play(ctx, head)
+}
+
+// Play applies the header 'h' of any player, as the Service does for headers received from the network.
+// Unsuccessful guesses are only emitted as events.
+func (b *MemoryBackend) Play(ctx context.Context, h *model.Header) error {
+	err := h.ValidateBasic(b.genesis.Rules)
+	if err != nil {
+		return err
+	}
+
+	err = h.ValidateNext(b.chain.Head(), b.genesis.Rules)
+	switch model.ReasonOf(err) {
+	case 0:
+		if err != nil {
+			return err
+		}
+	case model.RejectWrongGuess:
+		b.events.emit(EvtGuessAttempt{Header: h})
+		b.log(fmt.Sprintf("unsuccessful guess from %s", h.PeerID))
+		return nil
+	default:
+		return err
+	}
+
+	err = b.chain.extend(ctx, h)
+	if err != nil {
+		return err
+	}
+
+	if h.IsSkip() {
+		b.log(fmt.Sprintf("round skipped by %s", h.PeerID))
+		return nil
+	}
+	b.events.emit(EvtGuessAttempt{Header: h, Successful: true})
+	b.log(fmt.Sprintf("successful guess from %s", h.PeerID))
+	return nil
+}
+
+// Rounds returns up to 'limit' latest finished rounds, starting from the latest one.
+func (b *MemoryBackend) Rounds(ctx context.Context, limit int) ([]*model.Round, error) {
+	return b.player.rounds(ctx, limit)
+}
+
+// SaveGame keeps the 'state' of the local player's game in the round started by the header with the 'round' hash.
+func (b *MemoryBackend) SaveGame(ctx context.Context, round multihash.Multihash, state *GameState) error {
+	return b.store.PutGame(ctx, round, state)
+}
+
+// LoadGame returns the state of the local player's game in the round started by the header with the 'round' hash.
+func (b *MemoryBackend) LoadGame(ctx context.Context, round multihash.Multihash) (*GameState, error) {
+	return b.store.Game(ctx, round)
+}
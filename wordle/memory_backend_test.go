@@ -0,0 +1,40 @@
+package wordle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBackend(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	backend := newTestBackend(ctx, t, "hello")
+	events := backend.Events(ctx)
+
+	require.NoError(t, backend.SubmitGuess(ctx, "wrong", "world"))
+	evt := (<-events).(EvtGuessAttempt)
+	require.False(t, evt.Successful)
+
+	require.NoError(t, backend.SubmitGuess(ctx, "hello", "world"))
+	head := (<-events).(EvtNewHead).Head
+	require.Equal(t, backend.ID(), head.PeerID)
+	require.True(t, (<-events).(EvtGuessAttempt).Successful)
+
+	// nobody solved our word yet
+	require.ErrorIs(t, backend.Timeout(ctx, "again"), errTooEarly)
+
+	next := playTestRound(ctx, t, backend, "world", "again")
+	require.Equal(t, next, (<-events).(EvtNewHead).Head)
+
+	rounds, err := backend.Rounds(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, rounds, 2)
+	require.Equal(t, "world", rounds[0].Word)
+	require.Equal(t, backend.ID(), rounds[0].Proposer)
+	require.Equal(t, next.PeerID, rounds[0].Solver)
+	require.Equal(t, "hello", rounds[1].Word)
+}
@@ -0,0 +1,188 @@
+package wordle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/libp2p/go-libp2p-core/crypto"
+
+	"github.com/p2p-games/wordle/dictionary"
+	"github.com/p2p-games/wordle/model"
+)
+
+var errTooEarly = errors.New("wordle: the round has not timed out yet")
+
+// localPlayer makes the moves of the local player on top of the chain, leaving their delivery to the caller.
+// It is shared by the GameBackends.
+type localPlayer struct {
+	id      string
+	key     crypto.PrivKey // signs dictionary selections
+	rules   model.Rules
+	saltLen int
+	// hard makes the rounds of our proposals hard
+	hard bool
+	// wordLen is the length of the words we propose, zero for any, accessed atomically
+	wordLen int32
+	store   *Store
+	chain   *chain
+}
+
+// guess makes the header guessing the current word with the 'guess' and proposing the 'proposal' next.
+func (p *localPlayer) guess(ctx context.Context, guess, proposal string) (*model.Header, error) {
+	head := p.chain.Head()
+	proposal, prop, sel, err := p.propose(head, proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := model.NewHeader(head, guess, prop, p.id)
+	if err != nil {
+		return nil, err
+	}
+	next.Selection = sel
+	next.HardMode = p.hard
+	if head.IsHard(p.rules) {
+		next.Attempts, err = p.attempts(ctx, head, next.Solution)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return next, p.store.PutProposal(ctx, next.Height, proposal)
+}
+
+// attempts returns our guesses in the round started by the 'head' before the 'guess',
+// as kept in the saved game of the round.
+func (p *localPlayer) attempts(ctx context.Context, head *model.Header, guess string) ([]string, error) {
+	round, err := head.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := p.store.Game(ctx, round)
+	switch err {
+	case nil:
+	case datastore.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	// the game is saved with the guess, before it is submitted
+	attempts := state.AttemptedWords
+	if n := len(attempts); n > 0 && attempts[n-1] == guess {
+		attempts = attempts[:n-1]
+	}
+	return attempts, nil
+}
+
+// timeout makes the header revealing our current word or skipping someone's, once the round timed out,
+// and proposing the 'proposal' next.
+func (p *localPlayer) timeout(ctx context.Context, proposal string) (*model.Header, error) {
+	head := p.chain.Head()
+	if head.PeerID == p.id {
+		if time.Now().Before(head.RevealAfter(p.rules)) {
+			return nil, errTooEarly
+		}
+
+		word, err := p.store.Proposal(ctx, head.Height)
+		if err != nil {
+			return nil, fmt.Errorf("wordle: getting our proposal: %w", err)
+		}
+		return p.guess(ctx, word, proposal)
+	}
+
+	if time.Now().Before(head.SkipAfter(p.rules)) {
+		return nil, errTooEarly
+	}
+
+	proposal, prop, sel, err := p.propose(head, proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := model.NewSkip(head, prop, p.id)
+	if err != nil {
+		return nil, err
+	}
+	next.Selection = sel
+	next.HardMode = p.hard
+
+	return next, p.store.PutProposal(ctx, next.Height, proposal)
+}
+
+// wordLength returns the length in letters of the words we propose. Zero means any length.
+func (p *localPlayer) wordLength() int {
+	return int(atomic.LoadInt32(&p.wordLen))
+}
+
+// setWordLen makes us propose words of 'n' letters, or of any length, if 'n' is zero.
+// In networks with a dictionary, our words are selected among its words of the length.
+func (p *localPlayer) setWordLen(n int) error {
+	if n != 0 && (n < p.rules.MinWordLen || n > p.rules.MaxWordLen) {
+		return fmt.Errorf("wordle: the word length must be from %d to %d", p.rules.MinWordLen, p.rules.MaxWordLen)
+	}
+	if n != 0 && p.rules.Dictionary != "" {
+		dict, err := dictionary.Get(p.rules.Dictionary)
+		if err != nil {
+			return err
+		}
+		if len(dict.Words(n)) == 0 {
+			return fmt.Errorf("wordle: no %d letter words in the '%s' dictionary, there are %v",
+				n, dict.Name(), dict.Lens())
+		}
+	}
+
+	atomic.StoreInt32(&p.wordLen, int32(n))
+	return nil
+}
+
+// propose commits to the 'word' we propose on top of the 'parent'.
+// If the network selects words from a dictionary, the selected word is proposed instead and returned.
+func (p *localPlayer) propose(parent *model.Header, word string) (string, *model.Word, *model.Selection, error) {
+	var sel *model.Selection
+	if p.rules.Dictionary != "" {
+		dict, err := dictionary.Get(p.rules.Dictionary)
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		word, sel, err = model.Select(dict, parent, p.key, p.wordLength())
+		if err != nil {
+			return "", nil, nil, err
+		}
+	}
+
+	if l := p.wordLength(); l != 0 && dictionary.Len(word) != l {
+		return "", nil, nil, fmt.Errorf("wordle: the word must be %d letters long, as chosen", l)
+	}
+	prop, err := model.NewProposal(word, p.saltLen)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return word, prop, sel, nil
+}
+
+// rounds returns up to 'limit' latest finished rounds, starting from the latest one.
+// There may be less of them, if we don't have older headers.
+func (p *localPlayer) rounds(ctx context.Context, limit int) ([]*model.Round, error) {
+	rounds := make([]*model.Round, 0, limit)
+	for next := p.chain.Head(); len(rounds) < limit && next.Height > 1; {
+		proposal, err := p.store.Get(ctx, next.Height-1)
+		switch err {
+		case nil:
+		case datastore.ErrNotFound:
+			return rounds, nil
+		default:
+			return nil, err
+		}
+
+		rounds = append(rounds, model.NewRound(proposal, next))
+		next = proposal
+	}
+	return rounds, nil
+}
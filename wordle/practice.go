@@ -0,0 +1,315 @@
+package wordle
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/dictionary"
+	"github.com/p2p-games/wordle/model"
+)
+
+// practicePeer proposes the words in practice.
+const practicePeer = "practice"
+
+// PracticeConfig configures the offline single-player practice.
+type PracticeConfig struct {
+	// Dictionary to draw words from.
+	Dictionary string
+	// WordLen is the length of the words in letters.
+	WordLen int
+	// MaxAttempts is the amount of guesses per word.
+	MaxAttempts int
+}
+
+// DefaultPracticeConfig returns the default PracticeConfig.
+func DefaultPracticeConfig() PracticeConfig {
+	return PracticeConfig{
+		Dictionary:  "en",
+		WordLen:     5,
+		MaxAttempts: MaxApptemps,
+	}
+}
+
+// PracticeStats are the personal results of the practice.
+type PracticeStats struct {
+	Played, Won       int
+	Streak, MaxStreak int
+	// Distribution counts the won words by the amount of attempts.
+	Distribution map[int]int
+}
+
+func (s *PracticeStats) String() string {
+	won := 0
+	if s.Played > 0 {
+		won = s.Won * 100 / s.Played
+	}
+	return fmt.Sprintf("Played %d, won %d%%, streak %d, max streak %d", s.Played, won, s.Streak, s.MaxStreak)
+}
+
+// PracticeBackend is a GameBackend for the offline single-player practice.
+// Every round is a random word from the dictionary, which moves on to the next one once the player solves it
+// or runs out of attempts. It keeps PracticeStats in the given datastore.
+type PracticeBackend struct {
+	id      string
+	genesis *model.Genesis
+	words   []string
+	ds      datastore.Datastore
+	events  *eventBus
+
+	lk       sync.Mutex
+	head     *model.Header
+	word     string
+	attempts int
+	rounds   []*model.Round
+	games    map[string]*GameState
+	stats    *PracticeStats
+
+	log func(string)
+}
+
+// NewPracticeBackend starts the practice of the player with the 'key', keeping the stats in the 'ds'.
+func NewPracticeBackend(
+	ctx context.Context,
+	ds datastore.Datastore,
+	key crypto.PrivKey,
+	cfg PracticeConfig,
+) (*PracticeBackend, error) {
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxAttempts <= 0 {
+		return nil, fmt.Errorf("wordle: invalid max attempts %d", cfg.MaxAttempts)
+	}
+
+	dict, err := dictionary.Get(cfg.Dictionary)
+	if err != nil {
+		return nil, err
+	}
+	words := dict.Words(cfg.WordLen)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("wordle: no %d letter words in the '%s' dictionary", cfg.WordLen, cfg.Dictionary)
+	}
+
+	b := &PracticeBackend{
+		id: id.String(),
+		// the genesis is never shared, it only defines the rules for the game
+		genesis: &model.Genesis{
+			ChainID: practicePeer,
+			Rules: model.Rules{
+				MinWordLen:  cfg.WordLen,
+				MaxWordLen:  cfg.WordLen,
+				MaxAttempts: cfg.MaxAttempts,
+				Dictionary:  cfg.Dictionary,
+			},
+		},
+		words:  words,
+		ds:     ds,
+		events: newEventBus(),
+		games:  make(map[string]*GameState),
+		stats:  &PracticeStats{Distribution: make(map[int]int)},
+		log:    func(string) {},
+	}
+
+	data, err := ds.Get(ctx, practiceStatsKey)
+	switch err {
+	case nil:
+		err = json.Unmarshal(data, b.stats)
+		if err != nil {
+			return nil, err
+		}
+		if b.stats.Distribution == nil {
+			b.stats.Distribution = make(map[int]int)
+		}
+	case datastore.ErrNotFound:
+	default:
+		return nil, err
+	}
+
+	return b, b.nextRound()
+}
+
+func (b *PracticeBackend) SetLog(log func(string)) {
+	b.log = log
+}
+
+// Stats returns the personal results of the practice.
+func (b *PracticeBackend) Stats() PracticeStats {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	stats := *b.stats
+	stats.Distribution = make(map[int]int, len(b.stats.Distribution))
+	for k, v := range b.stats.Distribution {
+		stats.Distribution[k] = v
+	}
+	return stats
+}
+
+// ID identifies the player in headers.
+func (b *PracticeBackend) ID() string {
+	return b.id
+}
+
+// Genesis returns the Genesis defining the rules of the practice.
+func (b *PracticeBackend) Genesis() *model.Genesis {
+	return b.genesis
+}
+
+// CurrentRound returns the header starting the current round.
+func (b *PracticeBackend) CurrentRound(context.Context) (*model.Header, error) {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	return b.head, nil
+}
+
+// Events returns a channel receiving Events until the 'ctx' is done.
+func (b *PracticeBackend) Events(ctx context.Context) <-chan Event {
+	return b.events.subscribe(ctx)
+}
+
+// SubmitGuess checks the 'guess' of the current word. There is nothing to propose in practice.
+func (b *PracticeBackend) SubmitGuess(ctx context.Context, guess, _ string) error {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+
+	h, err := model.NewHeader(b.head, guess, nil, b.id)
+	if err != nil {
+		return err
+	}
+
+	b.attempts++
+	solved := model.Verify(h.Guess, b.head.Proposal)
+	b.events.emit(EvtGuessAttempt{Header: h, Successful: solved})
+	switch {
+	case solved:
+		b.finish(ctx, true)
+	case b.attempts >= b.genesis.Rules.MaxAttempts:
+		b.finish(ctx, false)
+	default:
+		return nil
+	}
+	return b.nextRound()
+}
+
+// Timeout gives up on the current word, once the round timed out.
+func (b *PracticeBackend) Timeout(ctx context.Context, _ string) error {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	if time.Now().Before(b.head.SkipAfter(b.genesis.Rules)) {
+		return errTooEarly
+	}
+
+	b.finish(ctx, false)
+	return b.nextRound()
+}
+
+// Rounds returns up to 'limit' latest finished rounds, starting from the latest one.
+func (b *PracticeBackend) Rounds(_ context.Context, limit int) ([]*model.Round, error) {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+
+	rounds := make([]*model.Round, 0, limit)
+	for i := len(b.rounds) - 1; i >= 0 && len(rounds) < limit; i-- {
+		rounds = append(rounds, b.rounds[i])
+	}
+	return rounds, nil
+}
+
+// SaveGame keeps the 'state' of the game in the round started by the header with the 'round' hash.
+// Practice rounds do not survive restarts, so neither do their games.
+func (b *PracticeBackend) SaveGame(_ context.Context, round multihash.Multihash, state *GameState) error {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	b.games[round.B58String()] = state
+	return nil
+}
+
+// LoadGame returns the state of the game in the round started by the header with the 'round' hash.
+func (b *PracticeBackend) LoadGame(_ context.Context, round multihash.Multihash) (*GameState, error) {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	state, ok := b.games[round.B58String()]
+	if !ok {
+		return nil, datastore.ErrNotFound
+	}
+	return state, nil
+}
+
+// finish records the result of the current round and stores the stats.
+// Must be called under the lock.
+func (b *PracticeBackend) finish(ctx context.Context, won bool) {
+	round := &model.Round{
+		Height:   b.head.Height,
+		Word:     b.word,
+		Proposer: practicePeer,
+		Start:    time.Unix(b.head.Time, 0),
+		End:      time.Now(),
+	}
+
+	b.stats.Played++
+	if won {
+		round.Solver = b.id
+		b.stats.Won++
+		b.stats.Streak++
+		b.stats.Distribution[b.attempts]++
+		if b.stats.Streak > b.stats.MaxStreak {
+			b.stats.MaxStreak = b.stats.Streak
+		}
+	} else {
+		round.Revealed = true
+		b.stats.Streak = 0
+		b.log(fmt.Sprintf("The word was '%s'", b.word))
+	}
+	b.rounds = append(b.rounds, round)
+	b.log(b.stats.String())
+
+	data, err := json.Marshal(b.stats)
+	if err == nil {
+		err = b.ds.Put(ctx, practiceStatsKey, data)
+	}
+	if err != nil {
+		log.Errorw("saving practice stats", "err", err)
+	}
+}
+
+// nextRound starts a new round with a random word.
+// Must be called under the lock, unless the backend is not shared yet.
+func (b *PracticeBackend) nextRound() error {
+	idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(b.words))))
+	if err != nil {
+		return err
+	}
+	word := b.words[idx.Int64()]
+
+	prop, err := model.NewProposal(word, model.DefaultSaltLength)
+	if err != nil {
+		return err
+	}
+
+	height := 1
+	if b.head != nil {
+		height = b.head.Height + 1
+	}
+
+	b.head = &model.Header{
+		Height:   height,
+		Proposal: prop,
+		PeerID:   practicePeer,
+		Time:     time.Now().Unix(),
+	}
+	b.word, b.attempts = word, 0
+	b.events.emit(EvtNewHead{Head: b.head})
+	return nil
+}
+
+var practiceStatsKey = datastore.NewKey("stats")
@@ -0,0 +1,81 @@
+package wordle
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPracticeBackend(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	ds := sync.MutexWrap(datastore.NewMapDatastore())
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	cfg := DefaultPracticeConfig()
+	cfg.MaxAttempts = 2
+
+	backend, err := NewPracticeBackend(ctx, ds, key, cfg)
+	require.NoError(t, err)
+	first, err := backend.CurrentRound(ctx)
+	require.NoError(t, err)
+
+	// play the word through the game
+	game := NewWordGame(ctx, backend, first)
+	require.Equal(t, StateGuessing, game.State())
+	require.Error(t, game.NewStdinInput("toolong"))
+	require.NoError(t, game.NewStdinInput(wrongWord(backend.word)))
+	require.NoError(t, game.NewStdinInput(backend.word))
+	require.Equal(t, StateSolved, game.State())
+
+	second, err := backend.CurrentRound(ctx)
+	require.NoError(t, err)
+	require.Equal(t, first.Height+1, second.Height)
+
+	// run out of attempts
+	word := backend.word
+	require.NoError(t, backend.SubmitGuess(ctx, wrongWord(word), ""))
+	require.NoError(t, backend.SubmitGuess(ctx, wrongWord(word), ""))
+
+	rounds, err := backend.Rounds(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, rounds, 2)
+	require.Equal(t, word, rounds[0].Word)
+	require.True(t, rounds[0].Revealed)
+	require.Equal(t, backend.ID(), rounds[1].Solver)
+
+	expected := PracticeStats{Played: 2, Won: 1, Streak: 0, MaxStreak: 1, Distribution: map[int]int{2: 1}}
+	require.Equal(t, expected, backend.Stats())
+
+	// stats survive restarts
+	backend, err = NewPracticeBackend(ctx, ds, key, cfg)
+	require.NoError(t, err)
+	require.Equal(t, expected, backend.Stats())
+
+	// words of other lengths of the dictionary are practiced too
+	cfg.WordLen = 7
+	backend, err = NewPracticeBackend(ctx, ds, key, cfg)
+	require.NoError(t, err)
+	head, err := backend.CurrentRound(ctx)
+	require.NoError(t, err)
+	require.Len(t, head.Proposal.Chars, 7)
+
+	cfg.WordLen = 42
+	_, err = NewPracticeBackend(ctx, ds, key, cfg)
+	require.Error(t, err)
+}
+
+// wrongWord returns a word of the same length, which is not the 'word'.
+func wrongWord(word string) string {
+	if word[0] == 'z' {
+		return "y" + word[1:]
+	}
+	return "z" + word[1:]
+}
@@ -4,46 +4,100 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	msngr "github.com/celestiaorg/go-libp2p-messenger"
 	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
 	logging "github.com/ipfs/go-log/v2"
 	core "github.com/libp2p/go-libp2p-core"
-
+	"github.com/libp2p/go-libp2p-core/discovery"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/multiformats/go-multihash"
 
 	"github.com/p2p-games/wordle/model"
 )
 
 var log = logging.Logger("wordle")
 
-var topic = "wordle"
-
-var protoID protocol.ID = "/wordle/v0.0.1"
+// maxClockDrift is how far in the future headers may be created, due to clocks of peers being out of sync.
+var maxClockDrift = time.Minute
 
 // TODO(@Wondertan); If we are Full Node, sync every header
 
 type Service struct {
-	store  *Store
-	host   core.Host
-	pubsub *pubsub.PubSub
-	topic  *pubsub.Topic
+	cfg     Config
+	genesis *model.Genesis
+	protoID protocol.ID
+	store   *Store
+	chain   *chain
+	player  *localPlayer
+	events  *eventBus
+	host    core.Host
+	pubsub  *pubsub.PubSub
+	topic   *pubsub.Topic
+	sub     *pubsub.Subscription
+
+	// attempts of the players in the current round, if it is hard
+	attempts *attemptLog
+
+	// disc is used to find Full Nodes, if not nil
+	disc      discovery.Discovery
+	fullPeers *peerSet
+
+	// peers we verified to play from the same genesis
+	genesisHash  multihash.Multihash
+	genesisPeers *peerSet
 
 	// TODO(@Wondertan): improve messenger so it can handle msg types, thus avoiding the requirement to make an instance
 	//  for a type
 	reqs, resps *msngr.Messenger
+	exchange    *exchange
+	syncing     int32
 
 	bootsrapped chan struct{}
+	ctx         context.Context
 	cancel      context.CancelFunc
 
 	log func(string)
 }
 
-func NewService(host core.Host, ds datastore.Batching, pubsub *pubsub.PubSub) *Service {
+func NewService(
+	cfg Config,
+	genesis *model.Genesis,
+	host core.Host,
+	ds datastore.Batching,
+	pubsub *pubsub.PubSub,
+	disc discovery.Discovery,
+) *Service {
+	def := DefaultConfig()
+	if cfg.SaltLength == 0 {
+		cfg.SaltLength = def.SaltLength
+	}
+	if cfg.RequestRate == 0 {
+		cfg.RequestRate = def.RequestRate
+	}
+	if cfg.RequestBurst == 0 {
+		cfg.RequestBurst = def.RequestBurst
+	}
+	if cfg.MaxConcurrentRequests == 0 {
+		cfg.MaxConcurrentRequests = def.MaxConcurrentRequests
+	}
+
+	head, err := genesis.Header()
+	if err != nil {
+		panic(err)
+	}
+	genesisHash, err := head.Hash()
+	if err != nil {
+		panic(err)
+	}
+
+	protoID := protocolID(genesis.ChainID)
 	reqs, err := msngr.New(host, msngr.WithProtocols(protoID+"/req"), msngr.WithMessageType(&HeaderRequest{}))
 	if err != nil {
 		panic(err)
@@ -52,16 +106,43 @@ func NewService(host core.Host, ds datastore.Batching, pubsub *pubsub.PubSub) *S
 	if err != nil {
 		panic(err)
 	}
-	return &Service{
-		store:       NewStore(ds),
-		host:        host,
-		pubsub:      pubsub,
-		reqs:        reqs,
-		resps:       resps,
-		bootsrapped: make(chan struct{}),
+	store := NewStore(namespace.Wrap(ds, datastore.NewKey(genesis.ChainID)), head)
+	events := newEventBus()
+	chain := newChain(store, genesis.Rules, events)
+	serv := &Service{
+		cfg:     cfg,
+		genesis: genesis,
+		protoID: protoID,
+		store:   store,
+		chain:   chain,
+		player: &localPlayer{
+			id:      host.ID().String(),
+			key:     host.Peerstore().PrivKey(host.ID()),
+			rules:   genesis.Rules,
+			saltLen: cfg.SaltLength,
+			hard:    cfg.HardMode,
+			store:   store,
+			chain:   chain,
+		},
+		events:       events,
+		attempts:     newAttemptLog(),
+		host:         host,
+		pubsub:       pubsub,
+		disc:         disc,
+		fullPeers:    newPeerSet(),
+		genesisHash:  genesisHash,
+		genesisPeers: newPeerSet(),
+		reqs:         reqs,
+		resps:        resps,
+		exchange:     newExchange(reqs, resps),
+		bootsrapped:  make(chan struct{}),
 
 		log: func(s string) { fmt.Println(s) },
 	}
+	if err := serv.player.setWordLen(cfg.WordLen); err != nil {
+		log.Warnw("ignoring the configured word length", "err", err)
+	}
+	return serv
 }
 
 func (s *Service) SetLog(log func(string)) {
@@ -69,27 +150,48 @@ func (s *Service) SetLog(log func(string)) {
 }
 
 func (s *Service) Start(ctx context.Context) (err error) {
-	s.topic, err = s.pubsub.Join(topic)
+	s.topic, err = s.pubsub.Join(s.genesis.ChainID)
+	if err != nil {
+		return err
+	}
+
+	err = s.pubsub.RegisterTopicValidator(s.genesis.ChainID, s.validate)
 	if err != nil {
 		return err
 	}
 
-	err = s.pubsub.RegisterTopicValidator(topic, s.validate)
+	// subscribe to receive guesses, which are processed by the validator
+	s.sub, err = s.topic.Subscribe()
 	if err != nil {
 		return err
 	}
 
-	ctx, s.cancel = context.WithCancel(ctx)
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	ctx = s.ctx
+	err = s.chain.start(ctx)
+	if err != nil {
+		return err
+	}
+	err = s.startHandshakes(ctx)
+	if err != nil {
+		return err
+	}
+	if s.disc != nil {
+		go s.discoverFullPeers(ctx)
+	}
 	go s.bootstrap(ctx)
 	go s.listen(ctx)
+	go s.exchange.run(ctx)
+	go s.drain(ctx)
 	s.log("Started P2P Wordle")
 	return nil
 }
 
 func (s *Service) Stop(context.Context) error {
 	s.cancel()
-	s.host.RemoveStreamHandler(protoID)
-	err := s.pubsub.UnregisterTopicValidator(topic)
+	s.sub.Cancel()
+	s.host.RemoveStreamHandler(s.handshakeID())
+	err := s.pubsub.UnregisterTopicValidator(s.genesis.ChainID)
 	if err != nil {
 		return err
 	}
@@ -107,27 +209,87 @@ func (s *Service) Stop(context.Context) error {
 	return s.topic.Close()
 }
 
-func (s *Service) Head(ctx context.Context) (*model.Header, error) {
-	return s.store.Head(ctx)
+// Genesis returns the Genesis of the network the Service plays in.
+func (s *Service) Genesis() *model.Genesis {
+	return s.genesis
+}
+
+// ID identifies the local player in headers.
+func (s *Service) ID() string {
+	return s.player.id
+}
+
+// CurrentRound returns the header starting the current round, i.e. the head of the chain.
+func (s *Service) CurrentRound(context.Context) (*model.Header, error) {
+	return s.chain.Head(), nil
+}
+
+// Events returns a channel receiving Events of the Service until the 'ctx' is done.
+// All the subscribers observe the same state transitions in the same order.
+func (s *Service) Events(ctx context.Context) <-chan Event {
+	return s.events.subscribe(ctx)
+}
+
+// SubmitGuess publishes the 'guess' of the current word, proposing the 'proposal' next.
+func (s *Service) SubmitGuess(ctx context.Context, guess, proposal string) error {
+	return s.SubmitTeamGuess(ctx, guess, proposal, nil)
 }
 
-func (s *Service) Guess(ctx context.Context, guess, proposal string) error {
+// SubmitTeamGuess is SubmitGuess on behalf of the 'team', which is recorded in the header, if not nil.
+func (s *Service) SubmitTeamGuess(ctx context.Context, guess, proposal string, team *model.Team) error {
 	select {
 	case <-s.bootsrapped:
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 
-	head, err := s.store.Head(ctx)
+	head, err := s.player.guess(ctx, guess, proposal)
 	if err != nil {
 		return err
 	}
+	head.Team = team
+	return s.publish(ctx, head)
+}
+
+// WordLen returns the length in letters of the words we propose. Zero means any length.
+func (s *Service) WordLen() int {
+	return s.player.wordLength()
+}
 
-	head, err = model.NewHeader(head, guess, proposal, s.host.ID().String())
+// SetWordLen makes us propose words of 'n' letters, or of any length, if 'n' is zero.
+func (s *Service) SetWordLen(n int) error {
+	return s.player.setWordLen(n)
+}
+
+// Timeout reveals the current word we proposed and nobody solved during the round timeout,
+// or skips someone's word nobody solved or revealed during twice the timeout,
+// starting a new round with the given 'proposal'.
+func (s *Service) Timeout(ctx context.Context, proposal string) error {
+	head, err := s.player.timeout(ctx, proposal)
 	if err != nil {
 		return err
 	}
+	return s.publish(ctx, head)
+}
+
+// Rounds returns up to 'limit' latest finished rounds, starting from the latest one.
+// There may be less of them, if we don't have older headers.
+func (s *Service) Rounds(ctx context.Context, limit int) ([]*model.Round, error) {
+	return s.player.rounds(ctx, limit)
+}
+
+// SaveGame keeps the 'state' of the local player's game in the round started by the header with the 'round' hash.
+func (s *Service) SaveGame(ctx context.Context, round multihash.Multihash, state *GameState) error {
+	return s.store.PutGame(ctx, round, state)
+}
+
+// LoadGame returns the state of the local player's game in the round started by the header with the 'round' hash.
+func (s *Service) LoadGame(ctx context.Context, round multihash.Multihash) (*GameState, error) {
+	return s.store.Game(ctx, round)
+}
 
+// publish sends our new 'head' to the network.
+func (s *Service) publish(ctx context.Context, head *model.Header) error {
 	data, err := json.Marshal(head)
 	if err != nil {
 		return err
@@ -136,46 +298,38 @@ func (s *Service) Guess(ctx context.Context, guess, proposal string) error {
 	return s.topic.Publish(ctx, data)
 }
 
-func (s *Service) Guesses(ctx context.Context) (<-chan *model.Header, error) {
-	sub, err := s.topic.Subscribe()
+// challengeRecord wraps ChallengeResults published on the main topic, telling them apart from headers.
+type challengeRecord struct {
+	ChallengeResult *model.ChallengeResult
+}
+
+// PublishChallengeResult publishes the 'res' of a challenge we played on the main topic, as a record.
+func (s *Service) PublishChallengeResult(ctx context.Context, res *model.ChallengeResult) error {
+	data, err := json.Marshal(&challengeRecord{ChallengeResult: res})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	out := make(chan *model.Header, 4)
-	go func() {
-		defer sub.Cancel()
-		defer close(out)
-		for {
-			msg, err := sub.Next(ctx)
-			if err != nil {
-				return
-			}
-
-			if peer.ID(msg.From) == s.host.ID() {
-				// ignore self messages
-				continue
-			}
-
-			header := &model.Header{}
-			err = json.Unmarshal(msg.Data, header)
-			if err != nil {
-				log.Errorw("unmarshalling header", "err", err)
-				continue
-			}
+	return s.topic.Publish(ctx, data)
+}
 
-			select {
-			case out <- header:
-			case <-ctx.Done():
-				return
-			}
+// drain consumes guesses from the topic subscription.
+// They are handled by the validator already, so there is nothing left to do, but to keep receiving them.
+func (s *Service) drain(ctx context.Context) {
+	for {
+		_, err := s.sub.Next(ctx)
+		if err != nil {
+			return
 		}
-	}()
-
-	return out, nil
+	}
 }
 
 func (s *Service) validate(ctx context.Context, _ peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	record := &challengeRecord{}
+	if json.Unmarshal(msg.Data, record) == nil && record.ChallengeResult != nil {
+		return s.validateRecord(msg, record.ChallengeResult)
+	}
+
 	proposal := &model.Header{}
 	err := json.Unmarshal(msg.Data, proposal)
 	if err != nil {
@@ -183,25 +337,107 @@ func (s *Service) validate(ctx context.Context, _ peer.ID, msg *pubsub.Message)
 		return pubsub.ValidationReject
 	}
 
-	head, err := s.store.Head(ctx)
+	err = proposal.ValidateBasic(s.genesis.Rules)
 	if err != nil {
-		log.Errorw("getting local head", "err", err)
+		log.Errorw("invalid proposal", "from", msg.ReceivedFrom, "reason", model.ReasonOf(err), "err", err)
+		return pubsub.ValidationReject
+	}
+	if from := msg.GetFrom(); from != "" && from.String() != proposal.PeerID {
+		// headers are not signed, so the message binds them to their player, e.g. proposers can't reveal early
+		// or solvers be credited by posing as someone else
+		log.Errorw("header of another player", "from", msg.ReceivedFrom, "author", from, "peer", proposal.PeerID)
+		return pubsub.ValidationReject
+	}
+
+	if time.Unix(proposal.Time, 0).After(time.Now().Add(maxClockDrift)) {
+		// otherwise, rounds could be revealed or skipped early
+		log.Warnw("ignoring proposal from the future", "from", msg.ReceivedFrom, "time", proposal.Time)
 		return pubsub.ValidationIgnore
 	}
 
-	if head.Height < proposal.Height && model.Verify(head.Proposal, proposal.Guess) {
-		err = s.store.Append(ctx, proposal)
+	head := s.chain.Head()
+	err = proposal.ValidateNext(head, s.genesis.Rules)
+	switch model.ReasonOf(err) {
+	case 0:
+		if err != nil {
+			log.Errorw("validating proposal", "err", err)
+			return pubsub.ValidationIgnore
+		}
+		if !s.logAttempts(msg, head, proposal) {
+			return pubsub.ValidationReject
+		}
+
+		// the head may have moved since, so let the chain decide whether the proposal still extends it
+		err = s.chain.extend(ctx, proposal)
 		if err != nil {
-			log.Errorw("appending the successful proposal", "err", err)
+			// e.g. another successful proposal for the same head came first
+			log.Debugw("proposal lost fork choice", "height", proposal.Height, "err", err)
 			return pubsub.ValidationIgnore
 		}
 
+		if proposal.IsSkip() {
+			s.log("rcvd round skip")
+			break
+		}
+
+		s.events.emit(EvtGuessAttempt{Header: proposal, Successful: true})
 		s.log("rcvd successful guess")
-	} else {
+	case model.RejectWrongGuess:
+		if !s.logAttempts(msg, head, proposal) {
+			return pubsub.ValidationReject
+		}
+		// we allow unsuccessful guesses to be passed around the network, but we store only successful ones
+		s.events.emit(EvtGuessAttempt{Header: proposal})
 		s.log("rcvd unsuccessful guess")
+	case model.RejectHeight, model.RejectParent:
+		if proposal.Height > head.Height+1 {
+			// we missed some headers, so catch up with the sender, but don't propagate until we verify
+			go s.catchUp(s.ctx, proposal, msg.ReceivedFrom)
+			return pubsub.ValidationIgnore
+		}
+
+		// the proposal is not for our head, e.g. it is stale
+		log.Debugw("ignoring proposal", "height", proposal.Height, "reason", model.ReasonOf(err))
+		return pubsub.ValidationIgnore
+	default:
+		// the proposal links to our head, but does not follow its proposal
+		log.Errorw("invalid proposal", "from", msg.ReceivedFrom, "reason", model.ReasonOf(err), "err", err)
+		return pubsub.ValidationReject
 	}
 
-	// we allow unsuccessful guesses to be passed around the network, but we store only successful ones
+	return pubsub.ValidationAccept
+}
+
+// logAttempts checks that a guess of the hard round started by the 'head' does not hide attempts of its player
+// we received before, and logs it. It reports whether the guess is valid.
+// Headers are not signed, so guesses are attributed to players by the signed author of the message, as validated.
+func (s *Service) logAttempts(msg *pubsub.Message, head, guess *model.Header) bool {
+	if guess.IsSkip() || !head.IsHard(s.genesis.Rules) {
+		return true
+	}
+
+	if msg.GetFrom() == "" {
+		// without signatures, anyone could publish guesses of others to make their real ones look hiding attempts
+		return true
+	}
+
+	err := s.attempts.add(head, guess)
+	if err != nil {
+		log.Errorw("hidden attempts", "from", msg.ReceivedFrom, "peer", guess.PeerID, "err", err)
+		return false
+	}
+	return true
+}
+
+// validateRecord checks the result of a challenge published as a record.
+func (s *Service) validateRecord(msg *pubsub.Message, res *model.ChallengeResult) pubsub.ValidationResult {
+	err := res.Validate(s.genesis.Rules)
+	if err != nil {
+		log.Errorw("invalid challenge record", "from", msg.ReceivedFrom, "err", err)
+		return pubsub.ValidationReject
+	}
+
+	s.events.emit(EvtChallengeResult{Result: res})
 	return pubsub.ValidationAccept
 }
 
@@ -210,6 +446,9 @@ func (s *Service) bootstrap(ctx context.Context) {
 	// discovery is done automagically by PubSub
 	// we just wait here until we discover and connect us to at least one peer for now
 	s.ensurePeers(ctx)
+	if ctx.Err() != nil {
+		return
+	}
 
 	headers := s.askPeers(ctx)
 	if len(headers) == 0 {
@@ -218,49 +457,121 @@ func (s *Service) bootstrap(ctx context.Context) {
 		return
 	}
 
-	// now, find if there is mismatch between headers on the same height
-	newHead := headers[0]
-	hashA, err := newHead.Hash()
+	newHead, err := s.pickHead(headers)
 	if err != nil {
+		// TODO(@Wondertan):
+		//  The whole point of this project was to implement p2p IVGs to make trust minimized access to the latest
+		//  state from the light clients. However, we don't have enough time to make this and we simply do
+		//  nothing when there is a mismatch between information. It shouldn't be hard to add the verification part
+		//  in here at later point.
+		fmt.Println(`
+Peers we are connected, told us different information about the network state.
+Something suspicious is happening. Just don't do anything for now, until we implement dispute resolution.
+			`)
 		return
 	}
+
+	// the head is only trusted once the whole chain down to ours is verified
+	err = s.syncTo(ctx, newHead, headPeer(headers, newHead))
+	switch err {
+	case nil:
+	case errStaleHead:
+		// we caught up in the meantime
+	default:
+		log.Errorw("syncing to the head", "height", newHead.Height, "err", err)
+		return
+	}
+
+	s.events.emit(EvtSyncProgress{Height: newHead.Height, Target: newHead.Height})
+	s.log(fmt.Sprintf("Updated the state! New height is %d. 'Guess what?' \n", newHead.Height))
+	close(s.bootsrapped)
+}
+
+var errDispute = errors.New("wordle: peers disagree on the network state")
+
+// pickHead chooses the new head out of the given 'headers' reported by peers.
+// If peers disagree, only Full Nodes are trusted to resolve the dispute.
+func (s *Service) pickHead(headers map[peer.ID]*model.Header) (*model.Header, error) {
+	head, err := agreedHeader(headers)
+	if err == nil {
+		return head, nil
+	}
+	if err != errDispute {
+		return nil, err
+	}
+
+	full := make(map[peer.ID]*model.Header)
+	for p, h := range headers {
+		if s.fullPeers.Has(p) {
+			full[p] = h
+		}
+	}
+	if len(full) == 0 {
+		return nil, errDispute
+	}
+
+	head, err = agreedHeader(full)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log("Peers disagreed on the network state, but Full Nodes resolved it")
+	return head, nil
+}
+
+// headPeer returns a peer out of the given 'headers' that reported the 'head'.
+func headPeer(headers map[peer.ID]*model.Header, head *model.Header) peer.ID {
+	for p, h := range headers {
+		if h == head {
+			return p
+		}
+	}
+	return ""
+}
+
+// agreedHeader returns the header all the given 'headers' are equal to or errDispute otherwise.
+func agreedHeader(headers map[peer.ID]*model.Header) (*model.Header, error) {
+	var (
+		head *model.Header
+		hash multihash.Multihash
+	)
 	for _, h := range headers {
 		hashB, err := h.Hash()
 		if err != nil {
-			return
+			return nil, err
 		}
 
-		if !bytes.Equal(hashA, hashB) {
-			// TODO(@Wondertan):
-			//  The whole point of this project was to implement p2p IVGs to make trust minimized access to the latest
-			//  state from the light clients. However, we don't have enough time to make this and we simply do
-			//  nothing when there is a mismatch between information. It shouldn't be hard to add the verification part
-			//  in here at later point.
-			fmt.Println(`
-Peers we are connected, told us different information about the network state.
-Something suspicious is happening. Just don't do anything for now, until we implement dispute resolution.
-			`)
-			return
+		if head == nil {
+			head, hash = h, hashB
+			continue
 		}
-	}
 
-	err = s.store.Append(ctx, newHead)
-	if err != nil {
-		log.Errorw("appending header", "err", err)
-		return
+		if !bytes.Equal(hash, hashB) {
+			return nil, errDispute
+		}
 	}
 
-	s.log(fmt.Sprintf("Updated the state! New height is %d. 'Guess what?' \n", newHead.Height))
-	close(s.bootsrapped)
+	return head, nil
 }
 
+// ensurePeers waits until we are connected to at least one peer and to the configured minimum of Full Nodes.
+// If the Full Nodes are not found in time, we proceed with whatever peers we have.
 func (s *Service) ensurePeers(ctx context.Context) {
 	t := time.NewTicker(time.Second)
 	defer t.Stop()
+
+	deadline := time.Now().Add(s.cfg.FullPeersTimeout)
 	for {
-		if len(s.reqs.Peers()) >= 1 {
-			s.log("Yay! Discovered some peers")
-			return
+		if len(s.peers()) >= 1 {
+			if s.disc == nil || len(s.connectedFullPeers()) >= s.cfg.MinFullPeers {
+				s.log("Yay! Discovered some peers")
+				return
+			}
+
+			if time.Now().After(deadline) {
+				s.log("Yay! Discovered some peers, but not enough Full Nodes")
+				return
+			}
 		}
 
 		select {
@@ -271,73 +582,138 @@ func (s *Service) ensurePeers(ctx context.Context) {
 	}
 }
 
-func (s *Service) askPeers(ctx context.Context) []*model.Header {
-	s.reqs.Broadcast(ctx, &HeaderRequest{Height: 0}) // request status from every connected peer
+// askPeers requests the latest header from the peers and returns the highest valid ones above our head,
+// keyed by peers they came from.
+// Full Nodes are asked exclusively, if there are enough of them.
+func (s *Service) askPeers(ctx context.Context) map[peer.ID]*model.Header {
+	peers := s.connectedFullPeers()
+	if len(peers) == 0 || len(peers) < s.cfg.MinFullPeers {
+		// there are not enough Full Nodes around, so ask everyone
+		peers = s.peers()
+	}
 
-	head, err := s.store.Head(ctx)
-	if err != nil {
-		log.Errorw("getting head", "err", err)
-		return nil
+	type result struct {
+		from peer.ID
+		head *model.Header
+	}
+	results := make(chan result, len(peers))
+	for _, p := range peers {
+		go func(p peer.ID) {
+			h, err := s.exchange.request(ctx, p, 0) // request status from the peer
+			if err != nil {
+				// the peer did not respond in time, go with what others have
+				log.Debugw("requesting head", "peer", p, "err", err)
+			}
+			results <- result{from: p, head: h}
+		}(p)
 	}
 
+	head := s.chain.Head()
 	s.log(fmt.Sprintf("JFYI, anon, we are on the height %d \n", head.Height))
 
 	height := head.Height
-	headers := make(map[int][]*model.Header)
-	for _, p := range s.reqs.Peers() {
-		s.host.ConnManager().TagPeer(p, topic, 100)
-		msg, _, err := s.resps.Receive(ctx)
-		if err != nil {
-			return nil
+	headers := make(map[int]map[peer.ID]*model.Header)
+	for range peers {
+		res := <-results
+		from, h := res.from, res.head
+		if h == nil {
+			continue
 		}
+		if h.Height > head.Height {
+			if err := h.ValidateBasic(s.genesis.Rules); err != nil {
+				// malformed heads would break the game, so they never become candidates
+				log.Errorw("invalid head from peer", "peer", from, "reason", model.ReasonOf(err), "err", err)
+				continue
+			}
+		}
+		s.host.ConnManager().TagPeer(from, s.genesis.ChainID, 100)
 
-		h := msg.(*HeaderResponse).Header
-		if h == nil {
+		if h.Height <= head.Height {
 			continue
 		}
 
 		if h.Height > height {
 			height = h.Height
-			headers[height] = append(headers[height], h)
 		}
+		if headers[h.Height] == nil {
+			headers[h.Height] = make(map[peer.ID]*model.Header)
+		}
+		headers[h.Height][from] = h
 	}
 
 	return headers[height]
 }
 
+// peers returns connected peers speaking the protocol, which are verified to play from the same genesis.
+func (s *Service) peers() []peer.ID {
+	peers := s.reqs.Peers()
+	verified := make([]peer.ID, 0, len(peers))
+	for _, p := range peers {
+		if s.genesisPeers.Has(p) {
+			verified = append(verified, p)
+		}
+	}
+	return verified
+}
+
+// listen serves header requests from peers.
+// Requests above the per peer rate or the concurrency limit are dropped, so the peers time out and try others.
 func (s *Service) listen(ctx context.Context) {
+	limiter := newRateLimiter(s.cfg.RequestRate, s.cfg.RequestBurst)
+	serving := make(chan struct{}, s.cfg.MaxConcurrentRequests)
 	for {
 		msg, from, err := s.reqs.Receive(ctx)
 		if err != nil {
 			return
 		}
-		req := msg.(*HeaderRequest)
 
-		resp := &HeaderResponse{}
-		switch req.Height {
-		case 0:
-			resp.Header, err = s.store.Head(ctx)
-			if err != nil {
-				log.Errorw("getting head", "err", err)
-				continue
-			}
-		default:
-			resp.Header, err = s.store.Get(ctx, req.Height)
-			if err != nil {
-				log.Errorw("getting header", "height", req.Height, "err", err)
-				continue
-			}
+		if !limiter.Allow(from) {
+			log.Debugw("dropping request over the rate limit", "peer", from)
+			continue
 		}
 
-		err = <-s.resps.Send(ctx, resp, from)
-		if err != nil {
-			log.Errorw("responding peer", "peer", from, "err", err)
+		select {
+		case serving <- struct{}{}:
+		default:
+			log.Debugw("dropping request over the concurrency limit", "peer", from)
 			continue
 		}
+
+		go func(req *HeaderRequest, from peer.ID) {
+			defer func() { <-serving }()
+			s.respond(ctx, req, from)
+		}(msg.(*HeaderRequest), from)
+	}
+}
+
+// respond sends the header requested by the peer 'from'.
+func (s *Service) respond(ctx context.Context, req *HeaderRequest, from peer.ID) {
+	resp := &HeaderResponse{ID: req.ID}
+	switch req.Height {
+	case 0:
+		resp.Header = s.chain.Head()
+	default:
+		var err error
+		resp.Header, err = s.store.Get(ctx, req.Height)
+		switch err {
+		case nil:
+		case datastore.ErrNotFound:
+			// respond with nothing, so the peer does not wait for us
+		default:
+			log.Errorw("getting header", "height", req.Height, "err", err)
+			return
+		}
+	}
+
+	err := <-s.resps.Send(ctx, resp, from)
+	if err != nil {
+		log.Errorw("responding peer", "peer", from, "err", err)
 	}
 }
 
 type HeaderRequest struct {
+	// ID correlates the request with its response.
+	ID     uint64
 	Height int // 0 means give me the latest
 }
 
@@ -360,6 +736,8 @@ func (h *HeaderRequest) Unmarshal(bytes []byte) error {
 }
 
 type HeaderResponse struct {
+	// ID is the ID of the request responded to.
+	ID     uint64
 	Header *model.Header
 }
 
@@ -2,17 +2,27 @@ package wordle
 
 import (
 	"context"
+	"crypto/rand"
+	"fmt"
+	gosync "sync"
 	"testing"
 	"time"
 
 	"github.com/ipfs/go-datastore"
 	"github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/discovery"
 	"github.com/libp2p/go-libp2p-core/event"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/p2p-games/wordle/dictionary"
 	"github.com/p2p-games/wordle/model"
 )
 
@@ -32,7 +42,7 @@ func TestService(t *testing.T) {
 		ps, err := pubsub.NewFloodSub(ctx, h, pubsub.WithMessageSignaturePolicy(pubsub.StrictNoSign))
 		require.NoError(t, err)
 
-		servs[i] = NewService(h, ds, ps)
+		servs[i] = NewService(DefaultConfig(), DefaultGenesis(), h, ds, ps, nil)
 		err = servs[i].Start(ctx)
 		require.NoError(t, err)
 		subs[i], err = net.Hosts()[0].EventBus().Subscribe(&event.EvtPeerIdentificationCompleted{})
@@ -52,24 +62,31 @@ func TestService(t *testing.T) {
 		}
 	}
 
-	guesses := make([]<-chan *model.Header, peers)
+	events := make([]<-chan Event, peers)
 	for i, serv := range servs {
-		guesses[i], err = serv.Guesses(ctx)
-		require.NoError(t, err)
+		events[i] = serv.Events(ctx)
 	}
 
-	prev := topic
+	prev := DefaultGenesis().ChainID // the first word of the default network
 	for _, serv := range servs {
 		prop := model.RandomString(5)
-		err := serv.Guess(ctx, prev, prop)
+		err := serv.SubmitGuess(ctx, prev, prop)
 		require.NoError(t, err)
 		prev = prop
 		time.Sleep(time.Millisecond * 50)
 	}
 
-	for _, sub := range guesses {
-		for range make([]bool, peers-1) {
-			<-sub
+	// every Service observes every new head, including its own
+	for _, sub := range events {
+		for heads := 0; heads < peers; {
+			select {
+			case evt := <-sub:
+				if _, ok := evt.(EvtNewHead); ok {
+					heads++
+				}
+			case <-ctx.Done():
+				t.Fatal(ctx.Err())
+			}
 		}
 	}
 
@@ -81,3 +98,410 @@ func TestService(t *testing.T) {
 		assert.Equal(t, head, headCpr)
 	}
 }
+
+// newTestService starts a standalone Service with the given 'head' as its local head,
+// ready to accept guesses without waiting for bootstrap.
+func newTestService(ctx context.Context, t *testing.T, head *model.Header) *Service {
+	net, err := mocknet.FullMeshLinked(1)
+	require.NoError(t, err)
+
+	h := net.Hosts()[0]
+	ps, err := pubsub.NewFloodSub(ctx, h, pubsub.WithMessageSignaturePolicy(pubsub.StrictNoSign))
+	require.NoError(t, err)
+
+	serv := NewService(DefaultConfig(), DefaultGenesis(), h, sync.MutexWrap(datastore.NewMapDatastore()), ps, nil)
+	err = serv.store.Append(ctx, head)
+	require.NoError(t, err)
+
+	err = serv.Start(ctx)
+	require.NoError(t, err)
+	close(serv.bootsrapped)
+	return serv
+}
+
+func TestService_FullPeers(t *testing.T) {
+	const peers = 3
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	net, err := mocknet.FullMeshLinked(peers)
+	require.NoError(t, err)
+
+	provs := &mockProviders{provs: make(map[string][]peer.AddrInfo)}
+	servs := make([]*Service, peers)
+	for i, h := range net.Hosts() {
+		ds := sync.MutexWrap(datastore.NewMapDatastore())
+		ps, err := pubsub.NewFloodSub(ctx, h, pubsub.WithMessageSignaturePolicy(pubsub.StrictNoSign))
+		require.NoError(t, err)
+
+		servs[i] = NewService(DefaultConfig(), DefaultGenesis(), h, ds, ps, &mockDiscovery{host: h, provs: provs})
+	}
+
+	// the first is the Full Node and the second lies about the state
+	full, liar, light := servs[0], servs[1], servs[2]
+	Advertise(ctx, full.disc, full.genesis.ChainID)
+	appendTestChain(ctx, t, full, "honest", 4)
+	appendTestChain(ctx, t, liar, "liar", 4)
+
+	for _, serv := range servs {
+		err = serv.Start(ctx)
+		require.NoError(t, err)
+	}
+
+	err = net.ConnectAllButSelf()
+	require.NoError(t, err)
+
+	select {
+	case <-light.bootsrapped:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	assert.True(t, light.fullPeers.Has(full.host.ID()))
+	assert.False(t, light.fullPeers.Has(liar.host.ID()))
+
+	head, err := light.store.Head(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "honest", head.PeerID)
+}
+
+// appendTestChain stores 'n' headers on top of the genesis of the 'serv', the last one solved by the 'solver'.
+func appendTestChain(ctx context.Context, t *testing.T, serv *Service, solver string, n int) {
+	head, err := serv.store.Head(ctx)
+	require.NoError(t, err)
+
+	prev := DefaultGenesis().ChainID
+	for i := 0; i < n; i++ {
+		word := fmt.Sprintf("word%d", i)
+		prop, err := model.NewProposal(word, model.DefaultSaltLength)
+		require.NoError(t, err)
+
+		id := solver
+		if i < n-1 {
+			id = fmt.Sprintf("%s-%d", solver, i)
+		}
+		head, err = model.NewHeader(head, prev, prop, id)
+		require.NoError(t, err)
+		err = serv.store.Append(ctx, head)
+		require.NoError(t, err)
+		prev = word
+	}
+}
+
+// mockProviders keeps advertisements of mockDiscovery.
+type mockProviders struct {
+	lk    gosync.Mutex
+	provs map[string][]peer.AddrInfo
+}
+
+// mockDiscovery is an in-memory discovery.Discovery.
+type mockDiscovery struct {
+	host  host.Host
+	provs *mockProviders
+}
+
+func (m *mockDiscovery) Advertise(_ context.Context, ns string, _ ...discovery.Option) (time.Duration, error) {
+	m.provs.lk.Lock()
+	defer m.provs.lk.Unlock()
+	m.provs.provs[ns] = append(m.provs.provs[ns], *host.InfoFromHost(m.host))
+	return time.Hour, nil
+}
+
+func (m *mockDiscovery) FindPeers(_ context.Context, ns string, _ ...discovery.Option) (<-chan peer.AddrInfo, error) {
+	m.provs.lk.Lock()
+	defer m.provs.lk.Unlock()
+	out := make(chan peer.AddrInfo, len(m.provs.provs[ns]))
+	for _, p := range m.provs.provs[ns] {
+		out <- p
+	}
+	close(out)
+	return out, nil
+}
+
+func TestService_Handshake(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	net, err := mocknet.FullMeshLinked(3)
+	require.NoError(t, err)
+
+	// same chain, but a different first word
+	other, err := model.NewGenesis(DefaultGenesis().ChainID, "other", DefaultGenesis().Rules)
+	require.NoError(t, err)
+
+	servs := make([]*Service, 3)
+	for i, g := range []*model.Genesis{DefaultGenesis(), DefaultGenesis(), other} {
+		h := net.Hosts()[i]
+		ps, err := pubsub.NewFloodSub(ctx, h, pubsub.WithMessageSignaturePolicy(pubsub.StrictNoSign))
+		require.NoError(t, err)
+
+		servs[i] = NewService(DefaultConfig(), g, h, sync.MutexWrap(datastore.NewMapDatastore()), ps, nil)
+		err = servs[i].Start(ctx)
+		require.NoError(t, err)
+	}
+
+	err = net.ConnectAllButSelf()
+	require.NoError(t, err)
+
+	a, b, c := servs[0], servs[1], servs[2]
+	require.Eventually(t, func() bool {
+		return a.genesisPeers.Has(b.host.ID()) && b.genesisPeers.Has(a.host.ID())
+	}, time.Second*3, time.Millisecond*50)
+
+	require.Eventually(t, func() bool {
+		return a.host.Network().Connectedness(c.host.ID()) != network.Connected
+	}, time.Second*3, time.Millisecond*50)
+	assert.False(t, a.genesisPeers.Has(c.host.ID()))
+	assert.False(t, c.genesisPeers.Has(a.host.ID()))
+	assert.NotContains(t, c.peers(), a.host.ID())
+	assert.Empty(t, c.peers())
+}
+
+func TestDefaultGenesis(t *testing.T) {
+	h, err := DefaultGenesis().Header()
+	require.NoError(t, err)
+
+	hash, err := h.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultGenesisHash, hash.B58String())
+}
+
+func TestService_CatchUp(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	net, err := mocknet.FullMeshLinked(2)
+	require.NoError(t, err)
+
+	servs := make([]*Service, 2)
+	for i, h := range net.Hosts() {
+		ps, err := pubsub.NewFloodSub(ctx, h, pubsub.WithMessageSignaturePolicy(pubsub.StrictNoSign))
+		require.NoError(t, err)
+
+		ds := sync.MutexWrap(datastore.NewMapDatastore())
+		servs[i] = NewService(DefaultConfig(), DefaultGenesis(), h, ds, ps, nil)
+		err = servs[i].Start(ctx)
+		require.NoError(t, err)
+	}
+
+	err = net.ConnectAllButSelf()
+	require.NoError(t, err)
+
+	ahead, behind := servs[0], servs[1]
+	for _, serv := range servs {
+		select {
+		case <-serv.bootsrapped:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	// grow the chain, so that the peer behind misses some headers
+	head, err := ahead.CurrentRound(ctx)
+	require.NoError(t, err)
+	prev := DefaultGenesis().ChainID
+	for _, word := range []string{"apple", "berry", "cherry"} {
+		prop, err := model.NewProposal(word, model.DefaultSaltLength)
+		require.NoError(t, err)
+
+		head, err = model.NewHeader(head, prev, prop, "player-"+word)
+		require.NoError(t, err)
+		err = ahead.chain.extend(ctx, head)
+		require.NoError(t, err)
+		prev = word
+	}
+
+	err = ahead.SubmitGuess(ctx, prev, "dates")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		head, err := behind.CurrentRound(ctx)
+		return err == nil && head.Height == 5
+	}, time.Second*5, time.Millisecond*50)
+
+	headA, err := ahead.CurrentRound(ctx)
+	require.NoError(t, err)
+	headB, err := behind.CurrentRound(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, headA, headB)
+
+	rounds, err := behind.Rounds(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, rounds, 4)
+	for i, word := range []string{"cherry", "berry", "apple", DefaultGenesis().ChainID} {
+		assert.Equal(t, word, rounds[i].Word)
+		assert.Equal(t, 4-i, rounds[i].Height)
+	}
+	assert.Equal(t, "player-berry", rounds[1].Proposer)
+	assert.Equal(t, "player-cherry", rounds[1].Solver)
+}
+
+func TestService_CatchUpAbsurdHeight(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	net, err := mocknet.FullMeshLinked(2)
+	require.NoError(t, err)
+
+	servs := make([]*Service, 2)
+	for i, h := range net.Hosts() {
+		ps, err := pubsub.NewFloodSub(ctx, h, pubsub.WithMessageSignaturePolicy(pubsub.StrictNoSign))
+		require.NoError(t, err)
+
+		ds := sync.MutexWrap(datastore.NewMapDatastore())
+		servs[i] = NewService(DefaultConfig(), DefaultGenesis(), h, ds, ps, nil)
+		err = servs[i].Start(ctx)
+		require.NoError(t, err)
+	}
+
+	err = net.ConnectAllButSelf()
+	require.NoError(t, err)
+
+	serv, liar := servs[0], servs[1]
+	for _, serv := range servs {
+		select {
+		case <-serv.bootsrapped:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	head, err := serv.CurrentRound(ctx)
+	require.NoError(t, err)
+	prop, err := model.NewProposal("world", model.DefaultSaltLength)
+	require.NoError(t, err)
+	target, err := model.NewHeader(head, DefaultGenesis().ChainID, prop, liar.ID())
+	require.NoError(t, err)
+	target.Height = 1 << 62
+
+	err = target.ValidateBasic(serv.Genesis().Rules)
+	assert.Equal(t, model.RejectHeight, model.ReasonOf(err))
+
+	// even if such a height got through, syncing towards it must not allocate for it
+	err = serv.syncTo(ctx, target, liar.host.ID())
+	assert.Error(t, err)
+	serv.catchUp(ctx, target, liar.host.ID())
+
+	current, err := serv.CurrentRound(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, head, current)
+}
+
+func TestService_SignedHeaders(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// messages are signed, so peers need real keys
+	net := mocknet.New()
+	for i := 0; i < 2; i++ {
+		key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(t, err)
+		_, err = net.AddPeer(key, ma.StringCast(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", 4500+i)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, net.LinkAll())
+
+	servs := make([]*Service, 2)
+	for i, h := range net.Hosts() {
+		ps, err := pubsub.NewFloodSub(ctx, h)
+		require.NoError(t, err)
+
+		ds := sync.MutexWrap(datastore.NewMapDatastore())
+		servs[i] = NewService(DefaultConfig(), DefaultGenesis(), h, ds, ps, nil)
+		servs[i].SetLog(func(string) {})
+		require.NoError(t, servs[i].Start(ctx))
+	}
+
+	require.NoError(t, net.ConnectAllButSelf())
+	for _, serv := range servs {
+		select {
+		case <-serv.bootsrapped:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	liar, other := servs[0], servs[1]
+	head, err := liar.CurrentRound(ctx)
+	require.NoError(t, err)
+	prop, err := model.NewProposal("world", model.DefaultSaltLength)
+	require.NoError(t, err)
+
+	// posing as another player
+	spoofed, err := model.NewHeader(head, DefaultGenesis().ChainID, prop, other.ID())
+	require.NoError(t, err)
+	require.Error(t, liar.publish(ctx, spoofed))
+
+	require.NoError(t, liar.SubmitGuess(ctx, DefaultGenesis().ChainID, "world"))
+	require.Eventually(t, func() bool {
+		return other.chain.Head().Height == 2
+	}, time.Second*5, time.Millisecond*50)
+	assert.Equal(t, liar.ID(), other.chain.Head().PeerID)
+}
+
+func TestService_Dictionary(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	rules := DefaultGenesis().Rules
+	rules.Dictionary = "en"
+	g, err := model.NewGenesis("dictionary", "hello", rules)
+	require.NoError(t, err)
+
+	// selections are signed, so peers need real keys
+	net := mocknet.New()
+	for i := 0; i < 2; i++ {
+		key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(t, err)
+		_, err = net.AddPeer(key, ma.StringCast(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", 4000+i)))
+		require.NoError(t, err)
+	}
+	err = net.LinkAll()
+	require.NoError(t, err)
+
+	servs := make([]*Service, 2)
+	for i, h := range net.Hosts() {
+		ps, err := pubsub.NewFloodSub(ctx, h, pubsub.WithMessageSignaturePolicy(pubsub.StrictNoSign))
+		require.NoError(t, err)
+
+		servs[i] = NewService(DefaultConfig(), g, h, sync.MutexWrap(datastore.NewMapDatastore()), ps, nil)
+		err = servs[i].Start(ctx)
+		require.NoError(t, err)
+	}
+
+	err = net.ConnectAllButSelf()
+	require.NoError(t, err)
+	for _, serv := range servs {
+		select {
+		case <-serv.bootsrapped:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	proposer, other := servs[0], servs[1]
+	require.Error(t, proposer.SetWordLen(8))
+	require.NoError(t, proposer.SetWordLen(6))
+	err = proposer.SubmitGuess(ctx, "hello", "gibberish") // the proposal is ignored
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		head, err := other.CurrentRound(ctx)
+		return err == nil && head.Height == 2
+	}, time.Second*5, time.Millisecond*50)
+
+	word, err := proposer.store.Proposal(ctx, 2)
+	require.NoError(t, err)
+	dict, err := dictionary.Get("en")
+	require.NoError(t, err)
+	assert.True(t, dict.Contains(word))
+	assert.Equal(t, 6, dictionary.Len(word))
+
+	head, err := other.CurrentRound(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 6, head.Selection.Len)
+	correct, err := model.VerifyString(word, head.Proposal)
+	require.NoError(t, err)
+	assert.True(t, IsGuessSuccess(correct))
+}
@@ -1,31 +1,52 @@
 package wordle
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"strconv"
 
 	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"github.com/multiformats/go-multihash"
 
 	"github.com/p2p-games/wordle/model"
 )
 
-// use topic name as genesis
-var genesis, _ = model.NewHeader(&model.Header{Proposal: &model.Word{}}, "", topic, "")
+// DefaultGenesisHash is the well-known hash of the first header of the default public network.
+// Any change to it splits the network.
+const DefaultGenesisHash = "Qma2fgbp4rrFTFUJ9mAfcUZzajNpB3Z3iuSaHBGjXnhnLN"
+
+// DefaultGenesis returns the Genesis of the default public network.
+// Its first word is the chain ID, so every node computes the same Genesis.
+func DefaultGenesis() *model.Genesis {
+	g, err := model.NewOpenGenesis("wordle", model.Rules{
+		MinWordLen:  MinWordLen,
+		MaxWordLen:  MaxWordLen,
+		MaxAttempts: MaxApptemps,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
 
 type Store struct {
-	ds datastore.Batching
+	ds      datastore.Batching
+	genesis *model.Header
 }
 
-func NewStore(ds datastore.Batching) *Store {
+func NewStore(ds datastore.Batching, genesis *model.Header) *Store {
 	return &Store{
-		ds: ds,
+		ds:      ds,
+		genesis: genesis,
 	}
 }
 
 func (s *Store) Init(ctx context.Context) error {
-	return s.Append(ctx, genesis)
+	return s.Append(ctx, s.genesis)
 }
 
 func (s *Store) Head(ctx context.Context) (*model.Header, error) {
@@ -39,7 +60,7 @@ func (s *Store) Head(ctx context.Context) (*model.Header, error) {
 			return nil, err
 		}
 
-		return genesis, nil
+		return s.genesis, nil
 	case nil:
 		headHeight, _ := binary.Uvarint(data)
 		if err != nil {
@@ -50,24 +71,30 @@ func (s *Store) Head(ctx context.Context) (*model.Header, error) {
 	}
 }
 
+// Append stores the header 'h' and makes it the head in a single batch.
 func (s *Store) Append(ctx context.Context, h *model.Header) error {
 	data, err := json.Marshal(h)
 	if err != nil {
 		return err
 	}
 
-	err = s.ds.Put(ctx, datastore.NewKey(strconv.Itoa(h.Height)), data)
+	b, err := s.ds.Batch(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = b.Put(ctx, datastore.NewKey(strconv.Itoa(h.Height)), data)
 	if err != nil {
 		return err
 	}
 
 	data = make([]byte, 8)
 	n := binary.PutUvarint(data, uint64(h.Height))
-	err = s.ds.Put(ctx, headKey, data[:n])
+	err = b.Put(ctx, headKey, data[:n])
 	if err != nil {
 		return err
 	}
-	return nil
+	return b.Commit(ctx)
 }
 
 func (s *Store) Get(ctx context.Context, height int) (*model.Header, error) {
@@ -80,4 +107,181 @@ func (s *Store) Get(ctx context.Context, height int) (*model.Header, error) {
 	return h, json.Unmarshal(data, &h)
 }
 
-var headKey = datastore.NewKey("head")
+// PutProposal keeps the plaintext 'word' we proposed in the header at the given 'height',
+// so that we can reveal it, if nobody solves it.
+func (s *Store) PutProposal(ctx context.Context, height int, word string) error {
+	return s.ds.Put(ctx, proposalKey(height), []byte(word))
+}
+
+// Proposal returns the plaintext word we proposed in the header at the given 'height'.
+func (s *Store) Proposal(ctx context.Context, height int) (string, error) {
+	data, err := s.ds.Get(ctx, proposalKey(height))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GameState is the progress of the local player in a round.
+type GameState struct {
+	State          State
+	NextWord       string
+	AttemptedWords []string
+	Events         []GameEvent
+}
+
+// PutGame keeps the 'state' of the game in the round started by the header with the given 'hash',
+// so that restarting the node neither loses it, nor resets the attempts.
+func (s *Store) PutGame(ctx context.Context, hash multihash.Multihash, state *GameState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.ds.Put(ctx, gameKey(hash), data)
+}
+
+// Game returns the state of the game in the round started by the header with the given 'hash'.
+func (s *Store) Game(ctx context.Context, hash multihash.Multihash) (*GameState, error) {
+	data, err := s.ds.Get(ctx, gameKey(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	state := &GameState{}
+	return state, json.Unmarshal(data, state)
+}
+
+// descends reports whether the stored chain links down to the genesis header of the Store.
+// An empty Store descends from it.
+func (s *Store) descends(ctx context.Context) (bool, error) {
+	ok, err := s.ds.Has(ctx, headKey)
+	if err != nil || !ok {
+		return err == nil, err
+	}
+
+	h, err := s.Head(ctx)
+	if err != nil {
+		return false, err
+	}
+	for h.Height > 1 {
+		parent, err := s.Get(ctx, h.Height-1)
+		switch err {
+		case nil:
+		case datastore.ErrNotFound:
+			return false, nil
+		default:
+			return false, err
+		}
+
+		hash, err := parent.Hash()
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(hash, h.LastHeaderHash) {
+			return false, nil
+		}
+		h = parent
+	}
+
+	hash, err := h.Hash()
+	if err != nil {
+		return false, err
+	}
+	genesis, err := s.genesis.Hash()
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(hash, genesis), nil
+}
+
+// MigrateStore moves the data kept at the root of the 'ds' by nodes predating Genesis files into the namespace
+// of the Genesis 'g'. The chain among it is moved only if it descends from the first header of 'g', as it could
+// never link to the network's chain otherwise, and is dropped instead. Keys already in the namespace are kept.
+func MigrateStore(ctx context.Context, ds datastore.Batching, g *model.Genesis) error {
+	genesis, err := g.Header()
+	if err != nil {
+		return err
+	}
+	descends, err := NewStore(ds, genesis).descends(ctx)
+	if err != nil {
+		return fmt.Errorf("wordle: checking the chain to migrate: %w", err)
+	}
+
+	res, err := ds.Query(ctx, query.Query{})
+	if err != nil {
+		return err
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return err
+	}
+
+	b, err := ds.Batch(ctx)
+	if err != nil {
+		return err
+	}
+
+	ns := datastore.NewKey(g.ChainID)
+	var moved, dropped int
+	for _, e := range entries {
+		key := datastore.NewKey(e.Key)
+		if len(key.Namespaces()) != 1 {
+			// namespaced data, e.g. of practice games, is kept by nodes knowing Genesis files already
+			continue
+		}
+
+		if descends || !isChainKey(key) {
+			ok, err := ds.Has(ctx, ns.Child(key))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				err = b.Put(ctx, ns.Child(key), e.Value)
+				if err != nil {
+					return err
+				}
+			}
+			moved++
+		} else {
+			dropped++
+		}
+
+		err = b.Delete(ctx, key)
+		if err != nil {
+			return err
+		}
+	}
+	if moved == 0 && dropped == 0 {
+		return nil
+	}
+
+	if dropped > 0 {
+		log.Warnw("dropping the old chain, as it does not descend from the genesis",
+			"chain", g.ChainID, "keys", dropped)
+	}
+	log.Infow("moving old data into the namespace of the chain", "chain", g.ChainID, "keys", moved)
+	return b.Commit(ctx)
+}
+
+// isChainKey reports whether the 'key' at the root of a Store keeps its chain, i.e. the head or a header.
+func isChainKey(key datastore.Key) bool {
+	if key == headKey {
+		return true
+	}
+	_, err := strconv.Atoi(key.Name())
+	return err == nil
+}
+
+func gameKey(hash multihash.Multihash) datastore.Key {
+	return gamesKey.ChildString(hash.B58String())
+}
+
+func proposalKey(height int) datastore.Key {
+	return proposalsKey.ChildString(strconv.Itoa(height))
+}
+
+var (
+	headKey      = datastore.NewKey("head")
+	proposalsKey = datastore.NewKey("proposals")
+	gamesKey     = datastore.NewKey("games")
+)
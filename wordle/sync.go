@@ -0,0 +1,115 @@
+package wordle
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+// syncBatchSize is the amount of fetched headers the chain is extended with at once.
+// Heights are told by peers, so the headers are not kept in memory all the way to the target.
+const syncBatchSize = 256
+
+// catchUp fetches headers missing between our head and the 'target' received from peer 'from',
+// verifies they link up and fast-forwards our head to the 'target'.
+// Only one catch-up runs at a time, others are dropped, as the running one fast-forwards anyway.
+func (s *Service) catchUp(ctx context.Context, target *model.Header, from peer.ID) {
+	if !atomic.CompareAndSwapInt32(&s.syncing, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&s.syncing, 0)
+
+	head := s.chain.Head()
+	if target.Height <= head.Height+1 {
+		return // someone else caught up already
+	}
+
+	s.log(fmt.Sprintf("We are behind! Catching up from height %d to %d", head.Height, target.Height))
+	err := s.syncTo(ctx, target, from)
+	if err != nil {
+		// e.g. the head moved while we were fetching, the next received header triggers another catch-up if needed
+		log.Errorw("catching up", "height", target.Height, "err", err)
+		return
+	}
+
+	s.log(fmt.Sprintf("Caught up! New height is %d", target.Height))
+}
+
+// syncTo fetches the headers between our head and the 'target', preferably from peer 'from',
+// verifies every one of them links to the previous one down to our head and extends the chain up to the 'target'
+// in batches.
+func (s *Service) syncTo(ctx context.Context, target *model.Header, from peer.ID) error {
+	head := s.chain.Head()
+	if target.Height <= head.Height {
+		return errStaleHead
+	}
+
+	headers := make([]*model.Header, 0, syncBatchSize)
+	last := head
+	for height := head.Height + 1; height < target.Height; height++ {
+		h, err := s.fetchHeader(ctx, height, last, from)
+		if err != nil {
+			return err
+		}
+
+		headers = append(headers, h)
+		last = h
+		s.events.emit(EvtSyncProgress{Height: height, Target: target.Height})
+
+		if len(headers) == syncBatchSize {
+			// every fetched header is verified to link to our head already
+			err = s.chain.extend(ctx, headers...)
+			if err != nil {
+				return err
+			}
+			headers = make([]*model.Header, 0, syncBatchSize)
+		}
+	}
+
+	err := target.ValidateNext(last, s.genesis.Rules)
+	if err != nil {
+		return fmt.Errorf("wordle: the target does not link to the fetched headers: %w", err)
+	}
+	headers = append(headers, target)
+
+	return s.chain.extend(ctx, headers...)
+}
+
+// fetchHeader requests the header at the given 'height' following the 'parent' from peers,
+// starting with the 'preferred' one, until one of them responds with a valid header.
+func (s *Service) fetchHeader(
+	ctx context.Context,
+	height int,
+	parent *model.Header,
+	preferred peer.ID,
+) (*model.Header, error) {
+	peers := append([]peer.ID{preferred}, s.peers()...)
+	for i, p := range peers {
+		if i > 0 && p == preferred {
+			continue
+		}
+
+		h, err := s.exchange.request(ctx, p, height)
+		if err != nil {
+			log.Debugw("requesting header", "peer", p, "height", height, "err", err)
+			continue
+		}
+
+		err = h.ValidateBasic(s.genesis.Rules)
+		if err == nil {
+			err = h.ValidateNext(parent, s.genesis.Rules)
+		}
+		if err != nil {
+			log.Errorw("invalid header from peer", "peer", p, "height", height, "err", err)
+			continue
+		}
+
+		return h, nil
+	}
+
+	return nil, fmt.Errorf("wordle: no peer responded with a valid header at height %d", height)
+}
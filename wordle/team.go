@@ -0,0 +1,333 @@
+package wordle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+// teamMsg is published on team topics. It announces a member, or shares its guess of the word of a round.
+type teamMsg struct {
+	PeerID string
+	// Membership is the signed membership of the member, recorded in the headers of the team's solutions.
+	Membership *model.Membership `json:",omitempty"`
+	// Round is the hash of the header starting the round guessed in. Empty for announcements.
+	Round multihash.Multihash `json:",omitempty"`
+	Guess string              `json:",omitempty"`
+}
+
+// Team is a GameBackend for playing the rounds of the Service as a named team.
+// Members share their guesses on the team's own topic, see the ones of each other live and have one attempt budget
+// per round. The member solving the word publishes the solution on behalf of the whole team, which is recorded
+// in the header with the signed memberships of the members, so that all of them are credited for it.
+// Anyone knowing the name can join the team, as the name is the only secret of its topic.
+type Team struct {
+	name   string
+	serv   *Service
+	pubsub *pubsub.PubSub
+	topic  *pubsub.Topic
+	sub    *pubsub.Subscription
+	joins  *pubsub.TopicEventHandler
+	events *eventBus
+	cancel context.CancelFunc
+
+	lk      sync.Mutex
+	members map[string]*model.Membership
+	guesses map[string][]string // by the hash of the round
+}
+
+// NewTeam plays the rounds of the Service 'serv' in the team 'name'.
+func NewTeam(name string, serv *Service, pubsub *pubsub.PubSub) (*Team, error) {
+	if err := model.ValidateTeamName(name); err != nil {
+		return nil, err
+	}
+	membership, err := model.NewMembership(name, serv.player.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Team{
+		name:    name,
+		serv:    serv,
+		pubsub:  pubsub,
+		events:  newEventBus(),
+		members: map[string]*model.Membership{serv.ID(): membership},
+		guesses: make(map[string][]string),
+	}, nil
+}
+
+// teamTopic is the topic of the team 'name' in the network 'chainID'.
+// The name is hashed, so that it is not revealed to peers learning about our topics.
+func teamTopic(chainID, name string) string {
+	hash := sha256.Sum256([]byte(name))
+	mh, _ := multihash.Encode(hash[:], multihash.SHA2_256)
+	return fmt.Sprintf("%s/team/%s", chainID, multihash.Multihash(mh).B58String())
+}
+
+func (t *Team) Start(ctx context.Context) (err error) {
+	t.topic, err = t.pubsub.Join(teamTopic(t.serv.Genesis().ChainID, t.name))
+	if err != nil {
+		return err
+	}
+	t.sub, err = t.topic.Subscribe()
+	if err != nil {
+		return err
+	}
+
+	t.joins, err = t.topic.EventHandler()
+	if err != nil {
+		return err
+	}
+
+	var lctx context.Context
+	lctx, t.cancel = context.WithCancel(context.Background())
+	go t.listen(lctx)
+	go t.welcome(lctx)
+	go t.forward(lctx, t.serv.Events(lctx))
+	return t.announce(ctx)
+}
+
+func (t *Team) Stop(context.Context) error {
+	t.cancel()
+	t.joins.Cancel()
+	t.sub.Cancel()
+	return t.topic.Close()
+}
+
+func (t *Team) SetLog(log func(string)) {
+	t.serv.SetLog(log)
+}
+
+// Name returns the name of the team.
+func (t *Team) Name() string {
+	return t.name
+}
+
+// Members returns the PeerIDs of the known members of the team, including us.
+func (t *Team) Members() []string {
+	t.lk.Lock()
+	defer t.lk.Unlock()
+	return t.memberIDs()
+}
+
+// memberIDs returns the sorted PeerIDs of the known members. The lock must be held.
+func (t *Team) memberIDs() []string {
+	members := make([]string, 0, len(t.members))
+	for m := range t.members {
+		members = append(members, m)
+	}
+	sort.Strings(members)
+	return members
+}
+
+// WordLen returns the length in letters of the words we propose. Zero means any length.
+func (t *Team) WordLen() int {
+	return t.serv.WordLen()
+}
+
+// SetWordLen makes us propose words of 'n' letters, or of any length, if 'n' is zero.
+func (t *Team) SetWordLen(n int) error {
+	return t.serv.SetWordLen(n)
+}
+
+// ID identifies the local player in headers.
+func (t *Team) ID() string {
+	return t.serv.ID()
+}
+
+// Genesis returns the Genesis of the game.
+func (t *Team) Genesis() *model.Genesis {
+	return t.serv.Genesis()
+}
+
+// CurrentRound returns the header starting the current round.
+func (t *Team) CurrentRound(ctx context.Context) (*model.Header, error) {
+	return t.serv.CurrentRound(ctx)
+}
+
+// Events returns a channel receiving Events of the Service and EvtTeamGuesses of the other members
+// until the 'ctx' is done.
+func (t *Team) Events(ctx context.Context) <-chan Event {
+	return t.events.subscribe(ctx)
+}
+
+// SubmitGuess shares the 'guess' of the current word with the team, taking one of its attempts.
+// If it solves the word, it is published on behalf of the team, proposing the 'proposal' next.
+func (t *Team) SubmitGuess(ctx context.Context, guess, proposal string) error {
+	head, err := t.serv.CurrentRound(ctx)
+	if err != nil {
+		return err
+	}
+	round, err := head.Hash()
+	if err != nil {
+		return err
+	}
+	correct, err := model.VerifyString(guess, head.Proposal)
+	if err != nil {
+		return err
+	}
+
+	t.lk.Lock()
+	guesses := t.guesses[round.B58String()]
+	if len(guesses) >= t.Genesis().Rules.MaxAttempts {
+		t.lk.Unlock()
+		return fmt.Errorf("wordle: the team is out of attempts")
+	}
+	t.guesses[round.B58String()] = append(guesses, guess)
+	t.lk.Unlock()
+
+	err = t.publish(ctx, &teamMsg{PeerID: t.ID(), Round: round, Guess: guess})
+	if err != nil {
+		return err
+	}
+	if !IsGuessSuccess(correct) {
+		return nil
+	}
+	return t.serv.SubmitTeamGuess(ctx, guess, proposal, t.team())
+}
+
+// Timeout reveals our current word or skips someone's, once the round timed out, proposing the 'proposal' next.
+func (t *Team) Timeout(ctx context.Context, proposal string) error {
+	return t.serv.Timeout(ctx, proposal)
+}
+
+// Rounds returns up to 'limit' latest finished rounds, starting from the latest one.
+func (t *Team) Rounds(ctx context.Context, limit int) ([]*model.Round, error) {
+	return t.serv.Rounds(ctx, limit)
+}
+
+// SaveGame keeps the 'state' of the local player's game in the round started by the header with the 'round' hash.
+func (t *Team) SaveGame(ctx context.Context, round multihash.Multihash, state *GameState) error {
+	return t.serv.SaveGame(ctx, round, state)
+}
+
+// LoadGame returns the state of the local player's game in the round started by the header with the 'round' hash.
+func (t *Team) LoadGame(ctx context.Context, round multihash.Multihash) (*GameState, error) {
+	return t.serv.LoadGame(ctx, round)
+}
+
+// team returns the Team recorded in our solutions, with us and as many other members as fit.
+func (t *Team) team() *model.Team {
+	t.lk.Lock()
+	defer t.lk.Unlock()
+	members := []*model.Membership{t.members[t.ID()]}
+	for _, id := range t.memberIDs() {
+		if id != t.ID() && len(members) < model.MaxTeamSize {
+			members = append(members, t.members[id])
+		}
+	}
+	return &model.Team{Name: t.name, Members: members}
+}
+
+// announce tells the team we are in it.
+func (t *Team) announce(ctx context.Context) error {
+	return t.publish(ctx, &teamMsg{PeerID: t.ID()})
+}
+
+// publish sends the 'msg' with our membership to the team.
+func (t *Team) publish(ctx context.Context, msg *teamMsg) error {
+	t.lk.Lock()
+	msg.Membership = t.members[t.ID()]
+	t.lk.Unlock()
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return t.topic.Publish(ctx, data)
+}
+
+// listen receives the announcements and the guesses of the other members.
+func (t *Team) listen(ctx context.Context) {
+	for {
+		msg, err := t.sub.Next(ctx)
+		if err != nil {
+			return
+		}
+
+		tm := &teamMsg{}
+		err = json.Unmarshal(msg.Data, tm)
+		if err != nil {
+			log.Errorw("unmarshalling team message", "from", msg.ReceivedFrom, "err", err)
+			continue
+		}
+		if from := msg.GetFrom(); from != "" && from.String() != tm.PeerID {
+			// signed messages must come from the member itself
+			log.Warnw("ignoring team message of another peer", "from", from, "peer", tm.PeerID)
+			continue
+		}
+		if tm.PeerID == t.ID() {
+			continue
+		}
+
+		t.lk.Lock()
+		_, known := t.members[tm.PeerID]
+		if !known {
+			// members join with their signed membership, which we record in our solutions
+			if m := tm.Membership; m == nil || m.PeerID != tm.PeerID || m.Verify(t.name) != nil {
+				t.lk.Unlock()
+				log.Warnw("ignoring team message of an unknown member", "from", msg.ReceivedFrom, "peer", tm.PeerID)
+				continue
+			}
+			t.members[tm.PeerID] = tm.Membership
+		}
+		if tm.Guess != "" {
+			round := tm.Round.B58String()
+			if len(t.guesses[round]) < t.Genesis().Rules.MaxAttempts {
+				t.guesses[round] = append(t.guesses[round], tm.Guess)
+			}
+		}
+		t.lk.Unlock()
+
+		if !known {
+			t.events.emit(EvtTeamJoined{PeerID: tm.PeerID})
+			// let the new member know about us too
+			if err := t.announce(ctx); err != nil {
+				log.Errorw("announcing team member", "err", err)
+			}
+		}
+		if tm.Guess != "" {
+			t.events.emit(EvtTeamGuess{PeerID: tm.PeerID, Round: tm.Round, Guess: tm.Guess})
+		}
+	}
+}
+
+// welcome announces us to every peer subscribing to the team topic, as our earlier announcements
+// were not delivered to it.
+func (t *Team) welcome(ctx context.Context) {
+	for {
+		evt, err := t.joins.NextPeerEvent(ctx)
+		if err != nil {
+			return
+		}
+		if evt.Type != pubsub.PeerJoin {
+			continue
+		}
+		if err := t.announce(ctx); err != nil {
+			log.Errorw("announcing team member", "err", err)
+		}
+	}
+}
+
+// forward re-emits the Events of the Service.
+func (t *Team) forward(ctx context.Context, events <-chan Event) {
+	for {
+		select {
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+			t.events.emit(evt)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package wordle
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/p2p-games/wordle/model"
+)
+
+func TestTeam(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// team messages are signed, so peers need real keys
+	net := mocknet.New()
+	for i := 0; i < 2; i++ {
+		key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(t, err)
+		_, err = net.AddPeer(key, ma.StringCast(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", 4300+i)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, net.LinkAll())
+
+	_, err := NewTeam("night owls", nil, nil)
+	require.Error(t, err)
+
+	servs := make([]*Service, 2)
+	teams := make([]*Team, 2)
+	for i, h := range net.Hosts() {
+		ds := sync.MutexWrap(datastore.NewMapDatastore())
+		ps, err := pubsub.NewFloodSub(ctx, h)
+		require.NoError(t, err)
+
+		servs[i] = NewService(DefaultConfig(), DefaultGenesis(), h, ds, ps, nil)
+		servs[i].SetLog(func(string) {})
+		require.NoError(t, servs[i].Start(ctx))
+		teams[i], err = NewTeam("owls", servs[i], ps)
+		require.NoError(t, err)
+	}
+	require.NoError(t, net.ConnectAllButSelf())
+	for _, serv := range servs {
+		select {
+		case <-serv.bootsrapped:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	player, mate := teams[0], teams[1]
+	playerEvts, mateEvts := player.Events(ctx), mate.Events(ctx)
+	for _, team := range teams {
+		require.NoError(t, team.Start(ctx))
+		defer func(team *Team) { require.NoError(t, team.Stop(ctx)) }(team)
+	}
+
+	// members find each other, even if one announced itself before the other listened
+	require.Eventually(t, func() bool {
+		return len(player.Members()) == 2 && len(mate.Members()) == 2
+	}, time.Second*5, time.Millisecond*50)
+
+	// guesses are seen live by the team
+	require.NoError(t, player.SubmitGuess(ctx, "wordlz", "world"))
+	for {
+		evt, ok := nextEvent(ctx, t, mateEvts).(EvtTeamGuess)
+		if ok {
+			assert.Equal(t, player.ID(), evt.PeerID)
+			assert.Equal(t, "wordlz", evt.Guess)
+			break
+		}
+	}
+
+	// the solution is recorded on behalf of the team
+	require.NoError(t, mate.SubmitGuess(ctx, "wordle", "world"))
+	require.Eventually(t, func() bool {
+		return servs[0].chain.Head().Height == 2
+	}, time.Second*5, time.Millisecond*50)
+	head := servs[0].chain.Head()
+	assert.Equal(t, mate.ID(), head.PeerID)
+	require.NotNil(t, head.Team)
+	assert.Equal(t, "owls", head.Team.Name)
+	assert.Equal(t, []string{mate.ID(), player.ID()}, head.Team.Peers())
+
+	// and the whole team is credited for it, as every member signed its membership
+	rounds, err := player.Rounds(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{mate.ID(), player.ID()}, rounds[0].Team)
+	board := model.Leaderboard(rounds)
+	require.Len(t, board, 2)
+	for _, st := range board {
+		assert.Contains(t, []string{mate.ID(), player.ID()}, st.PeerID)
+		assert.Equal(t, 1, st.Solved)
+	}
+
+	// the team shares one budget of attempts
+	for {
+		if _, ok := nextEvent(ctx, t, playerEvts).(EvtNewHead); ok {
+			break
+		}
+	}
+	wrong := wrongWord("world")
+	for i := 0; i < MaxApptemps; i++ {
+		require.NoError(t, player.SubmitGuess(ctx, wrong, "hello"))
+	}
+	require.Error(t, player.SubmitGuess(ctx, "world", "hello"))
+	require.Eventually(t, func() bool {
+		mate.lk.Lock()
+		defer mate.lk.Unlock()
+		for _, guesses := range mate.guesses {
+			if len(guesses) == MaxApptemps && guesses[0] == wrong {
+				return true
+			}
+		}
+		return false
+	}, time.Second*5, time.Millisecond*50)
+	require.Error(t, mate.SubmitGuess(ctx, "world", "hello"))
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	tcell "github.com/gdamore/tcell/v2"
@@ -16,17 +19,33 @@ import (
 // chat prompt.
 type TerminalManager struct {
 	ctx      context.Context
-	Game     *WordGame
+	gameLk   sync.RWMutex
+	game     *WordGame
 	app      *tview.Application
 	debugBox *tview.TextView
 
 	stateBox     io.Writer
 	inputCh      chan string
 	OthersGuessC chan struct{}
+	// commands handles inputs before the game, reporting whether it did, if not nil
+	commands func(input string) (string, bool)
 
 	doneCh chan struct{}
 }
 
+const (
+	// historyCmd is typed to show the history of past rounds.
+	historyCmd = "/history"
+	// leaderboardCmd is typed to show the leaderboard of the day.
+	leaderboardCmd = "/leaderboard"
+	// lanesCmd is typed to list the lanes.
+	lanesCmd = "/lanes"
+	// laneCmd is typed with the index of the lane to play.
+	laneCmd = "/lane "
+	// lengthCmd is typed with the length of the words to propose, or zero for any length.
+	lengthCmd = "/length "
+)
+
 // NewTerminalManager returns a new TerminalManager struct that controls the text UI.
 // It won't actually do anything until you call Run().
 func NewTerminalManager(ctx context.Context, game *WordGame) *TerminalManager {
@@ -98,7 +117,7 @@ func NewTerminalManager(ctx context.Context, game *WordGame) *TerminalManager {
 	return &TerminalManager{
 		ctx:          ctx,
 		app:          app,
-		Game:         game,
+		game:         game,
 		stateBox:     stateBox,
 		debugBox:     debugB,
 		inputCh:      inputCh,
@@ -107,6 +126,26 @@ func NewTerminalManager(ctx context.Context, game *WordGame) *TerminalManager {
 	}
 }
 
+// Game returns the game being played.
+func (ui *TerminalManager) Game() *WordGame {
+	ui.gameLk.RLock()
+	defer ui.gameLk.RUnlock()
+	return ui.game
+}
+
+// SetGame replaces the game being played, e.g. when a new round starts.
+func (ui *TerminalManager) SetGame(game *WordGame) {
+	ui.gameLk.Lock()
+	ui.game = game
+	ui.gameLk.Unlock()
+}
+
+// SetCommands makes the 'commands' handle the inputs before the game.
+// They return the output to show and whether they handled the input. Must be called before Run.
+func (ui *TerminalManager) SetCommands(commands func(input string) (string, bool)) {
+	ui.commands = commands
+}
+
 func (ui *TerminalManager) Run() error {
 	go ui.handleEvents()
 	defer ui.end()
@@ -115,7 +154,7 @@ func (ui *TerminalManager) Run() error {
 	if err != nil {
 		return err
 	}
-	ui.displayStateString(ui.Game.ComposeStateUI())
+	ui.displayStateString(ui.Game().ComposeStateUI())
 	return nil
 
 }
@@ -130,7 +169,9 @@ func (ui *TerminalManager) displayStateString(s string) {
 }
 
 func (ui *TerminalManager) displayStateStatus() {
-	s := ui.Game.ComposeStateUI()
+	s := ui.Game().ComposeStateUI()
+	// the state is redrawn periodically, so replace the previous one
+	ui.stateBox.(*tview.TextView).Clear()
 	ui.displayStateString(s)
 }
 
@@ -145,27 +186,78 @@ func (ui *TerminalManager) AddDebugItem(s string) {
 }
 
 func (ui *TerminalManager) handleEvents() {
-	peerRefreshTicker := time.NewTicker(time.Second)
-	defer peerRefreshTicker.Stop()
+	// the ticker times rounds out and refreshes the state
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
 
 	for {
 		ui.displayStateStatus()
 		select {
 		case input := <-ui.inputCh:
-			switch ui.Game.StateIdx {
-			case 0:
+			if ui.commands != nil {
+				if out, ok := ui.commands(input); ok {
+					ui.AddDebugItem(out)
+					continue
+				}
+			}
+
+			game := ui.Game()
+			switch input {
+			case historyCmd:
+				ui.AddDebugItem(game.ComposeHistoryUI())
+				continue
+			case leaderboardCmd:
+				ui.AddDebugItem(game.ComposeLeaderboardUI())
+				continue
+			case lanesCmd:
+				ui.AddDebugItem(game.ComposeLanesUI())
+				continue
+			}
+			if strings.HasPrefix(input, lengthCmd) {
+				n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(input, lengthCmd)))
+				if err == nil {
+					err = game.SetWordLen(n)
+				}
+				switch {
+				case err != nil:
+					ui.AddDebugItem(fmt.Sprintf("unable to choose the word length: %s", err))
+				case n == 0:
+					ui.AddDebugItem("you propose words of any length")
+				default:
+					ui.AddDebugItem(fmt.Sprintf("you propose %d letter words", n))
+				}
+				continue
+			}
+			if strings.HasPrefix(input, laneCmd) {
+				lane, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(input, laneCmd)))
+				if err == nil {
+					err = game.SelectLane(lane)
+				}
+				if err != nil {
+					ui.AddDebugItem(fmt.Sprintf("unable to select the lane: %s", err))
+				}
+				continue
+			}
+
+			switch game.State() {
+			case StateProposing, StateSolved, StateRoundTimedOut:
 				ui.AddDebugItem(fmt.Sprintf("Your next proposed word: %s", input))
-			case 1:
+			case StateGuessing:
 				ui.AddDebugItem(fmt.Sprintf("Last guess: %s (freezes are expected if no peers connected)", input))
 			default:
 				continue
 			}
 			// when the user types in a line, publish it to the chat room and print to the message window
-			err := ui.Game.NewStdinInput(input)
+			err := game.NewStdinInput(input)
 			if err != nil {
 				ui.AddDebugItem(fmt.Sprintf("publish error: %s", err))
 			}
 
+		case now := <-ticker.C:
+			if ui.Game().Tick(now) {
+				ui.AddDebugItem("The round timed out")
+			}
+
 		case others := <-ui.OthersGuessC:
 			ui.AddDebugItem(fmt.Sprintln("new gueess from someone", others))
 			// when we receive a message from the chat room, print it to the message window
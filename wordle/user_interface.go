@@ -1,8 +1,12 @@
 package wordle
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"strings"
+
+	"github.com/libp2p/go-libp2p-core/peer"
 
 	"github.com/p2p-games/wordle/model"
 )
@@ -16,68 +20,137 @@ type WordleUI struct {
 
 	PeerId string
 
-	WordleServ  *Service
+	Backend     GameBackend
 	CurrentGame *WordGame
 
 	CannonicalHeader *model.Header
 
+	// Challenges are played in between the rounds of the Backend, if not nil
+	Challenges *Challenges
+	// challenged is true while playing a challenge
+	challenged bool
+
 	tm *TerminalManager
 }
 
-func NewWordleUI(ctx context.Context, wordleServ *Service, peerId string) *WordleUI {
+// logSetter is implemented by GameBackends with messages for the player.
+type logSetter interface {
+	SetLog(func(string))
+}
+
+func NewWordleUI(ctx context.Context, backend GameBackend) *WordleUI {
 
 	ui := &WordleUI{
-		ctx:        ctx,
-		PeerId:     peerId,
-		WordleServ: wordleServ,
+		ctx:     ctx,
+		PeerId:  backend.ID(),
+		Backend: backend,
 	}
 
-	wordleServ.SetLog(func(s string) {
-		ui.AddDebugItem(s)
-	})
+	if ls, ok := backend.(logSetter); ok {
+		ls.SetLog(func(s string) {
+			ui.AddDebugItem(s)
+		})
+	}
 
 	return ui
 }
 
+const (
+	// challengeCmd is typed with the peer ID and the word to challenge the peer with.
+	challengeCmd = "/challenge "
+	// acceptCmd is typed to play the next challenge received.
+	acceptCmd = "/accept"
+)
+
+// SetChallenges lets the player send, receive and play direct challenges. Must be called before Run.
+func (w *WordleUI) SetChallenges(challenges *Challenges) {
+	w.Challenges = challenges
+	challenges.SetLog(func(s string) {
+		w.AddDebugItem(s)
+	})
+}
+
 func (w *WordleUI) Run() {
 	var err error
 	// get the latest header from the server
-	w.CannonicalHeader, err = w.WordleServ.Head(w.ctx)
+	w.CannonicalHeader, err = w.Backend.CurrentRound(w.ctx)
 
 	if err != nil {
 		panic("non able to load any header from the datastore, not even genesis??!")
 	}
 
-	// generate a new game
-	w.CurrentGame = NewWordGame(w.ctx, w.PeerId, w.CannonicalHeader.PeerID, w.CannonicalHeader.Proposal, w.WordleServ)
+	// generate a new game, continuing the one we played before a restart
+	w.CurrentGame = NewWordGame(w.ctx, w.Backend, w.CannonicalHeader)
+	if err := w.CurrentGame.Restore(); err != nil {
+		log.Errorw("restoring game", "err", err)
+	}
 
 	// generate a terminal manager
 	w.tm = NewTerminalManager(w.ctx, w.CurrentGame)
+	w.tm.SetCommands(w.command)
+	// handle the events while the terminal runs, so the game follows the rounds
+	go w.handleEvents()
 	err = w.tm.Run()
 	if err != nil {
 		panic(err)
 	}
-	// get the channel for incoming headers
-	incomingHeaders, err := w.WordleServ.Guesses(w.ctx)
-	if err != nil {
-		panic("unable to retrieve the channel of headers from the user interface")
+}
+
+func (w *WordleUI) handleEvents() {
+	// the head changes on successful guesses, but also when the Service catches up with the network
+	events := w.Backend.Events(w.ctx)
+	var challenges <-chan Event
+	if w.Challenges != nil {
+		challenges = w.Challenges.Events(w.ctx)
 	}
 
 	for {
 		select {
-		case recHeader := <-incomingHeaders: // incoming New Message from surrounding peers
-			w.AddDebugItem(fmt.Sprintf("guess received from %s", recHeader.PeerID))
-			// verify weather the header is correct or not
-			if model.Verify(recHeader.Guess, w.CannonicalHeader.Proposal) {
-				w.CannonicalHeader = recHeader
-				// generate a new one game
-				w.CurrentGame = NewWordGame(w.ctx, w.PeerId, w.CannonicalHeader.PeerID, recHeader.Proposal, w.WordleServ)
-
-				// refresh the terminal manager
-				w.tm.Game = w.CurrentGame
-			} else {
-				// Actually, there isn't anything else to do
-				continue
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+			switch evt := evt.(type) {
+			case EvtGuessAttempt:
+				w.AddDebugItem(fmt.Sprintf("guess received from %s", evt.Header.PeerID))
+			case EvtDailyResult:
+				res := evt.Result
+				w.AddDebugItem(fmt.Sprintf("%s finished the puzzle of %s in %d attempts",
+					res.PeerID, res.Day, res.Attempts()))
+			case EvtLaneHead:
+				w.AddDebugItem(fmt.Sprintf("lane %d has a new word proposed by %s", evt.Lane, evt.Head.PeerID))
+			case EvtChallengeResult:
+				w.AddDebugItem(composeChallengeResult(evt.Result))
+			case EvtPeerJoined:
+				w.AddDebugItem(fmt.Sprintf("peer %s joined", evt.Peer))
+			case EvtTeamJoined:
+				w.AddDebugItem(fmt.Sprintf("%s joined the team", evt.PeerID))
+			case EvtTeamGuess:
+				w.addTeamGuess(evt)
+			case EvtNewHead:
+				w.CannonicalHeader = evt.Head
+				if !w.challenged {
+					w.newGame(w.Backend, evt.Head)
+				}
+			}
+		case evt, ok := <-challenges:
+			if !ok {
+				return
+			}
+			switch evt := evt.(type) {
+			case EvtChallenge:
+				w.AddDebugItem(fmt.Sprintf("%s challenges you, type %s to play", evt.Challenge.From, acceptCmd))
+			case EvtNewHead:
+				// the challenge is accepted, so play it until it is finished
+				w.challenged = true
+				w.newGame(w.Challenges, evt.Head)
+			case EvtChallengeResult:
+				w.AddDebugItem(composeChallengeResult(evt.Result))
+				if evt.Result.Challenge.To == w.PeerId {
+					// we finished the challenge, so get back to the round of the network
+					w.challenged = false
+					w.newGame(w.Backend, w.CannonicalHeader)
+				}
 			}
 		case <-w.ctx.Done(): // context shutdow
 			return
@@ -85,6 +158,84 @@ func (w *WordleUI) Run() {
 	}
 }
 
+// addTeamGuess shows the guess of a member of our team and takes it from our attempts,
+// if it is in the round we are playing.
+func (w *WordleUI) addTeamGuess(evt EvtTeamGuess) {
+	game := w.CurrentGame
+	if w.challenged || !bytes.Equal(game.Round(), evt.Round) {
+		return
+	}
+
+	w.AddDebugItem(fmt.Sprintf("%s guessed %s", evt.PeerID, ComposeWordleVisualWord(evt.Guess, game.Target)))
+	if err := game.AddTeamGuess(evt.Guess); err != nil {
+		log.Debugw("adding team guess", "peer", evt.PeerID, "err", err)
+	}
+}
+
+// command handles the inputs for challenges.
+func (w *WordleUI) command(input string) (string, bool) {
+	if w.Challenges == nil {
+		return "", false
+	}
+
+	switch {
+	case input == acceptCmd:
+		if err := w.Challenges.Accept(w.ctx); err != nil {
+			return fmt.Sprintf("unable to accept the challenge: %s", err), true
+		}
+		return "challenge accepted", true
+	case strings.HasPrefix(input, challengeCmd):
+		fields := strings.Fields(strings.TrimPrefix(input, challengeCmd))
+		if len(fields) != 2 {
+			return fmt.Sprintf("usage: %s<peer ID> <word>", challengeCmd), true
+		}
+		p, err := peer.Decode(fields[0])
+		if err != nil {
+			return fmt.Sprintf("invalid peer ID: %s", err), true
+		}
+
+		// sending may take a while, so don't block the input
+		go func() {
+			err := w.Challenges.Send(w.ctx, p, strings.ToLower(fields[1]))
+			if err != nil {
+				w.AddDebugItem(fmt.Sprintf("unable to challenge %s: %s", p, err))
+				return
+			}
+			w.AddDebugItem(fmt.Sprintf("challenged %s", p))
+		}()
+		return fmt.Sprintf("challenging %s", p), true
+	default:
+		return "", false
+	}
+}
+
+// composeChallengeResult describes the result of a challenge with its grid.
+func composeChallengeResult(res *model.ChallengeResult) string {
+	attempts := "x"
+	if res.Solved() {
+		attempts = fmt.Sprint(len(res.Guesses))
+	}
+
+	ch := res.Challenge
+	s := fmt.Sprintf("%s played the challenge of %s: %s/%d\n", ch.To, ch.From, attempts, ch.MaxAttempts)
+	for _, row := range res.Grid() {
+		s += fmt.Sprintf("\t%s\n", row)
+	}
+	return s
+}
+
+// newGame starts a new game of the 'backend' in the round started by the 'head'.
+func (w *WordleUI) newGame(backend GameBackend, head *model.Header) {
+	// generate a new one game
+	w.CurrentGame = NewWordGame(w.ctx, backend, head)
+	if err := w.CurrentGame.Restore(); err != nil {
+		log.Errorw("restoring game", "err", err)
+	}
+
+	// refresh the terminal manager
+	w.tm.SetGame(w.CurrentGame)
+}
+
 func (w *WordleUI) AddDebugItem(s string) {
 	w.tm.AddDebugItem(s)
 }
@@ -1,12 +1,12 @@
 package wordle
 
 import (
-	"crypto/sha256"
 	"fmt"
 	"os"
 	"os/exec"
 	"unicode"
 
+	"github.com/p2p-games/wordle/dictionary"
 	"github.com/p2p-games/wordle/model"
 )
 
@@ -49,49 +49,20 @@ func GetSaltsFromWord(word *model.Word) []string {
 var Green = "green"   // "\033[32m"
 var Yellow = "yellow" // "\033[33m"
 
+// ComposeWordleVisualWord colors every letter of the 'word' by its mark against the committed 'target' word.
 func ComposeWordleVisualWord(word string, target *model.Word) string {
-	// get the salts for the word
-	salts := GetSaltsFromWord(target)
-	var c []string
-	for _, l := range word {
-		c = append(c, string(l))
-	}
-	// compose the word
-	//chars, _ := model.GetChars(word, salts)
+	marks := []rune(model.GradeWord(word, target))
 
 	compWord := ""
-	h := sha256.New()
-	for i, char := range c {
-		charStatus := 0
-	out:
-		for _, s := range salts {
-			// check all the hashes of the letter with the different salts
-			h.Reset()
-			h.Write([]byte(char))
-			h.Write([]byte(s))
-			hashP := fmt.Sprintf("%x", h.Sum(nil))[:45]
-
-			for j, charB := range target.Chars {
-				if hashP == charB.Hash {
-					charStatus = 1
-					if i == j {
-						charStatus = 2
-						break out
-					}
-				}
-			}
+	for i, char := range dictionary.Letters(word) {
+		switch marks[i] {
+		case model.MarkAbsent: // not in the word
+			compWord += composeCharWithColor(char, "")
+		case model.MarkPresent: // in the word but on wrong possition
+			compWord += composeCharWithColor(char, Yellow)
+		case model.MarkCorrect: // bingo
+			compWord += composeCharWithColor(char, Green)
 		}
-		// once we know wheather it is or not, select color
-
-		switch charStatus {
-		case 0: // not in the word
-			compWord += composeCharWithColor(c[i], "")
-		case 1: // in the word but on wrong possition
-			compWord += composeCharWithColor(c[i], Yellow)
-		case 2: // bingo
-			compWord += composeCharWithColor(c[i], Green)
-		}
-
 	}
 	return compWord
 }
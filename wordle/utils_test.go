@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/p2p-games/wordle/model"
 )
 
@@ -38,4 +41,10 @@ func TestColorWords(t *testing.T) {
 	fmt.Println(ComposeWordleVisualWord("hello", target) + "\n")
 	fmt.Println(ComposeWordleVisualWord("eeeee", target) + "\n")
 	fmt.Println(ComposeWordleVisualWord("hella", target) + "\n")
+
+	// letters are marked as the model grades them, e.g. a repeated letter only as many times as it is in the word
+	prop, err := model.NewProposal("hello", model.DefaultSaltLength)
+	require.NoError(t, err)
+	assert.Equal(t, "[green]h[yellow]o[green]l[green]l[]y", ComposeWordleVisualWord("holly", prop))
+	assert.Equal(t, "[]l[]l[green]l[green]l[]l", ComposeWordleVisualWord("lllll", prop))
 }
@@ -4,138 +4,470 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync/atomic"
+	"sync"
+	"time"
 
+	"github.com/ipfs/go-datastore"
+	"github.com/multiformats/go-multihash"
+
+	"github.com/p2p-games/wordle/dictionary"
 	"github.com/p2p-games/wordle/model"
-	"github.com/pkg/errors"
 )
 
-const maxAttempts int = 5
-
+// WordGame is the game of the local player in the round started by a header.
+// It is safe for concurrent use.
 type WordGame struct {
 	ctx    context.Context
 	PeerId string
+	// header starting the round and its hash, under which the game is persisted
+	head  *model.Header
+	round multihash.Multihash
 
 	// to verify if the guess is correct
 	Target *model.Word
 	Salts  []string
 
-	StateIdx int32
-	NextWord string
-
-	AttemptedWords []string
+	lk             sync.Mutex
+	state          State
+	nextWord       string
+	attemptedWords []string
 	isCorrect      map[string][]bool
+	events         []GameEvent
 
-	serv *Service
+	rules    model.Rules
+	alphabet *dictionary.Alphabet // nil, if the network has no dictionary
+	backend  GameBackend
 }
 
-type guess struct {
-	Guess    string
-	Proposal string
-}
-
-// generate new game session
-func NewWordGame(ctx context.Context, peerId string, proposerId string, target *model.Word, serv *Service) *WordGame {
+// generate new game session for the round started by the 'head'
+func NewWordGame(ctx context.Context, backend GameBackend, head *model.Header) *WordGame {
+	target := head.Proposal
 	salts := GetSaltsFromWord(target)
+	round, err := head.Hash()
+	if err != nil {
+		log.Errorw("hashing round header", "err", err)
+	}
+
 	wg := &WordGame{
-		ctx:            ctx,
-		PeerId:         peerId,
-		Target:         target,
-		Salts:          salts,
-		StateIdx:       int32(0), // start requesting the word
-		AttemptedWords: make([]string, 0),
-		isCorrect:      make(map[string][]bool),
-		serv:           serv,
-	}
-	if proposerId == peerId {
-		// go straight to the 2 state (I already won)
-		atomic.StoreInt32(&wg.StateIdx, int32(2))
+		ctx:       ctx,
+		PeerId:    backend.ID(),
+		head:      head,
+		round:     round,
+		Target:    target,
+		Salts:     salts,
+		state:     StateProposing, // start requesting the word
+		isCorrect: make(map[string][]bool),
+		rules:     backend.Genesis().Rules,
+		backend:   backend,
+	}
+	if wg.rules.Dictionary != "" {
+		// the next word is selected from the dictionary, so go straight to guessing
+		wg.state = StateGuessing
+		// the genesis is validated, so the dictionary exists
+		dict, _ := dictionary.Get(wg.rules.Dictionary)
+		wg.alphabet = dict.Alphabet()
+	}
+	if head.PeerID == wg.PeerId {
+		// we proposed the word, so just watch others guessing it
+		wg.state = StateSpectating
 	}
 	return wg
 }
 
+// Restore continues the game of the round saved before a restart, if any.
+func (w *WordGame) Restore() error {
+	if w.round == nil {
+		return nil
+	}
+
+	state, err := w.backend.LoadGame(w.ctx, w.round)
+	switch err {
+	case nil:
+	case datastore.ErrNotFound:
+		return nil
+	default:
+		return err
+	}
+
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	for _, word := range state.AttemptedWords {
+		correct, err := model.VerifyString(word, w.Target)
+		if err != nil {
+			return err
+		}
+		w.isCorrect[word] = correct
+	}
+	w.state = state.State
+	w.nextWord = state.NextWord
+	w.attemptedWords = state.AttemptedWords
+	w.events = state.Events
+	return nil
+}
+
+// save persists the game, so that it can be restored after a restart.
+// Must be called under the lock.
+func (w *WordGame) save() {
+	if w.round == nil {
+		return
+	}
+
+	err := w.backend.SaveGame(w.ctx, w.round, &GameState{
+		State:          w.state,
+		NextWord:       w.nextWord,
+		AttemptedWords: w.attemptedWords,
+		Events:         w.events,
+	})
+	if err != nil {
+		log.Errorw("saving game", "err", err)
+	}
+}
+
+// transition moves the game to the State 'to' because of the 'input', logging and saving it.
+// Must be called under the lock.
+func (w *WordGame) transition(to State, input string) error {
+	if !w.state.CanGo(to) {
+		return fmt.Errorf("unable to go from %s to %s", w.state, to)
+	}
+
+	w.events = append(w.events, GameEvent{Time: time.Now(), From: w.state, To: to, Input: input})
+	w.state = to
+	w.save()
+	return nil
+}
+
+// Round returns the hash of the header starting the round of the game.
+func (w *WordGame) Round() multihash.Multihash {
+	return w.round
+}
+
+// State returns the current State of the game.
+func (w *WordGame) State() State {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	return w.state
+}
+
+// NextWord returns the word we propose next, if we solve the round.
+func (w *WordGame) NextWord() string {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	return w.nextWord
+}
+
+// AttemptedWords returns our guesses in the round.
+func (w *WordGame) AttemptedWords() []string {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	return append([]string(nil), w.attemptedWords...)
+}
+
+// Events returns the log of the game.
+func (w *WordGame) Events() []GameEvent {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	return append([]GameEvent(nil), w.events...)
+}
+
+// Tick times the round out, if the round timed out for us by the time 'now'
+// and there is nothing else to do in the round. It reports whether the round timed out.
+func (w *WordGame) Tick(now time.Time) bool {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	if now.Before(w.deadline()) || w.state == StateRoundTimedOut || !w.state.CanGo(StateRoundTimedOut) {
+		return false
+	}
+	return w.transition(StateRoundTimedOut, "") == nil
+}
+
 func (w *WordGame) ComposeStateUI() string {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
 	var s string
-	switch atomic.LoadInt32(&w.StateIdx) {
-	case int32(0):
+	switch w.state {
+	case StateProposing:
 		s = "Introduce your word proposal as next word to guess:\n"
-	case int32(1):
+		if l := w.wordLen(); l != 0 {
+			s = fmt.Sprintf("Introduce your %d letter word proposal as next word to guess:\n", l)
+		}
+	case StateGuessing:
 		s = "Guess which is the current Word:\n"
-		for _, guessedWord := range w.AttemptedWords {
-			if guessedWord != "" {
-				// check wheather the word was correct or not
-				correct := "x"
-				comp, err := model.VerifyString(guessedWord, w.Target)
-				if err != nil {
-					continue
-				}
-				if IsGuessSuccess(comp) {
-					correct = "v"
-				}
-				// compose the color strings with color chars
-
-				s += fmt.Sprintf("\t[%s] %s\n", correct, ComposeWordleVisualWord(guessedWord, w.Target))
+		if w.head.IsHard(w.rules) {
+			s = "Guess which is the current Word in hard mode, using all the hints:\n"
+		}
+		l := len(w.Target.Chars)
+		s += fmt.Sprintf("It has %d letters, %s\n", l, w.rules.Difficulty(l))
+		for _, guessedWord := range w.attemptedWords {
+			// check wheather the word was correct or not
+			correct := "x"
+			if IsGuessSuccess(w.isCorrect[guessedWord]) {
+				correct = "v"
 			}
+			// compose the color strings with color chars
+			s += fmt.Sprintf("\t[%s] %s\n", correct, ComposeWordleVisualWord(guessedWord, w.Target))
 		}
-		s += fmt.Sprintf("\nAttempts left %d\n", maxAttempts-len(w.AttemptedWords))
-	case int32(2):
-		s = "\n\tCongrats, you guessed the word!\nWait untill someone guesses your word to play again\n"
-	case int32(3):
+		s += fmt.Sprintf("\nAttempts left %d\n", w.rules.MaxAttempts-len(w.attemptedWords))
+	case StateSolved:
+		s = "\n\tCongrats, you guessed the word!\nWait untill the network accepts your solution to play again\n"
+		s += "If publishing it failed, introduce the word to propose to retry:\n"
+		s += w.timeoutHint()
+	case StateOutOfAttempts:
 		s = "\n\tNo more attempts left for this word!\nWait untill someone guesses it to play again\n"
+		s += w.timeoutHint()
+	case StateSpectating:
+		s = "\n\tOthers are guessing your word!\nWait untill someone guesses it to play again\n"
+		s += w.timeoutHint()
+	case StateRoundTimedOut:
+		if w.head.PeerID == w.PeerId {
+			s = "\n\tNobody guessed your word in time!\nIntroduce a new word to reveal yours:\n"
+		} else {
+			s = "\n\tNobody guessed the word in time!\nIntroduce a new word to skip it:\n"
+		}
 	default:
 		s = "unrecognized state to generate the UI\n"
 	}
 	return s
 }
 
-func (w *WordGame) NewStdinInput(input string) error {
-	// check if non alphanumeric character
-	input = strings.ToLower(input)
-	/*
-		if !IsLetter(input) {
-			return errors.New("not a letter")
+// historyRounds is the amount of past rounds shown in the history.
+const historyRounds = 10
+
+// ComposeHistoryUI lists the latest rounds with their words, proposers and solvers.
+func (w *WordGame) ComposeHistoryUI() string {
+	rounds, err := w.backend.Rounds(w.ctx, historyRounds)
+	if err != nil {
+		return fmt.Sprintf("unable to get the history: %s", err)
+	}
+	if len(rounds) == 0 {
+		return "no rounds played yet"
+	}
+
+	s := "Latest rounds:\n"
+	for _, r := range rounds {
+		switch {
+		case r.Skipped:
+			s += fmt.Sprintf("\t#%d skipped, proposed by %s\n", r.Height, r.Proposer)
+		case r.Revealed:
+			s += fmt.Sprintf("\t#%d '%s' revealed by %s\n", r.Height, r.Word, r.Proposer)
+		default:
+			s += fmt.Sprintf("\t#%d '%s' proposed by %s, solved by %s\n", r.Height, r.Word, r.Proposer, r.Solver)
 		}
-	*/
-	// check in which state do we are
-	switch atomic.LoadInt32(&w.StateIdx) {
-	case int32(0):
-		err := w.addNextTarget(input, w.Salts)
-		if err != nil {
-			return err
+	}
+	return s
+}
+
+// leaderboard is implemented by GameBackends ranking the results of players per day.
+type leaderboard interface {
+	Leaderboard(ctx context.Context, day string) ([]*model.DailyResult, error)
+}
+
+// ComposeLeaderboardUI ranks the results of players in the day of the round, which players report themselves.
+func (w *WordGame) ComposeLeaderboardUI() string {
+	lb, ok := w.backend.(leaderboard)
+	if !ok {
+		return w.composeStandingsUI()
+	}
+
+	day := model.Day(time.Unix(w.head.Time, 0))
+	board, err := lb.Leaderboard(w.ctx, day)
+	if err != nil {
+		return fmt.Sprintf("unable to get the leaderboard: %s", err)
+	}
+	if len(board) == 0 {
+		return fmt.Sprintf("no results for %s yet", day)
+	}
+
+	s := fmt.Sprintf("Leaderboard of %s, as reported by the players:\n", day)
+	for i, r := range board {
+		attempts := "x"
+		if r.Solved {
+			attempts = fmt.Sprint(r.Attempts())
 		}
-	case int32(1):
-		err := w.addNewGuess(input)
-		if err != nil {
-			fmt.Println("error adding new guess")
-			return err
+		s += fmt.Sprintf("\t%d. %s %s/%d\n", i+1, r.PeerID, attempts, w.rules.MaxAttempts)
+	}
+	return s
+}
+
+// standingsRounds is the amount of past rounds counted in the standings.
+const standingsRounds = 100
+
+// composeStandingsUI ranks the players by the words they solved in the latest rounds,
+// crediting every member of a team for the words the team solved.
+func (w *WordGame) composeStandingsUI() string {
+	rounds, err := w.backend.Rounds(w.ctx, standingsRounds)
+	if err != nil {
+		return fmt.Sprintf("unable to get the leaderboard: %s", err)
+	}
+	board := model.Leaderboard(rounds)
+	if len(board) == 0 {
+		return "no words solved yet"
+	}
+
+	s := "Leaderboard, weighing words by their length:\n"
+	for i, st := range board {
+		s += fmt.Sprintf("\t%d. %s %d points for %d words\n", i+1, st.PeerID, st.Score, st.Solved)
+	}
+	return s
+}
+
+// lanePicker is implemented by GameBackends playing several lanes.
+type lanePicker interface {
+	Len() int
+	Lane(i int) *Service
+	Selected() int
+	Select(i int) error
+}
+
+// ComposeLanesUI lists the lanes with their current words, marking the selected one.
+func (w *WordGame) ComposeLanesUI() string {
+	lp, ok := w.backend.(lanePicker)
+	if !ok {
+		return "there are no lanes in this game"
+	}
+
+	s := "Lanes:\n"
+	for i := 0; i < lp.Len(); i++ {
+		mark := " "
+		if i == lp.Selected() {
+			mark = "*"
 		}
+
+		head := lp.Lane(i).chain.Head()
+		if head.Height == 1 {
+			s += fmt.Sprintf("\t%s %d. not started, solve the first word of the network to start it\n", mark, i)
+			continue
+		}
+		s += fmt.Sprintf("\t%s %d. word #%d of %d letters proposed by %s\n",
+			mark, i, head.Height, len(head.Proposal.Chars), head.PeerID)
+	}
+	return s
+}
+
+// lengthPicker is implemented by GameBackends letting the local player choose the length of the words it proposes.
+type lengthPicker interface {
+	WordLen() int
+	SetWordLen(n int) error
+}
+
+// wordLen returns the length of the words we propose, if we chose one, or zero.
+func (w *WordGame) wordLen() int {
+	lp, ok := w.backend.(lengthPicker)
+	if !ok {
+		return 0
+	}
+	return lp.WordLen()
+}
+
+// SetWordLen makes us propose words of 'n' letters, or of any length, if 'n' is zero.
+// The word we already chose to propose must be of the length, as otherwise the proposal would be refused.
+func (w *WordGame) SetWordLen(n int) error {
+	lp, ok := w.backend.(lengthPicker)
+	if !ok {
+		return fmt.Errorf("the word length can not be chosen in this game")
+	}
+
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	if w.rules.Dictionary == "" && w.nextWord != "" && n != 0 && dictionary.Len(w.nextWord) != n {
+		return fmt.Errorf("the word you propose, '%s', is not %d letters long, choose the length in the next round",
+			w.nextWord, n)
+	}
+	return lp.SetWordLen(n)
+}
+
+// SelectLane switches to the puzzle of the lane 'i'.
+func (w *WordGame) SelectLane(i int) error {
+	lp, ok := w.backend.(lanePicker)
+	if !ok {
+		return fmt.Errorf("there are no lanes in this game")
+	}
+	return lp.Select(i)
+}
+
+func (w *WordGame) NewStdinInput(input string) error {
+	input = dictionary.Normalize(strings.ToLower(input))
+	// check in which state do we are
+	switch st := w.State(); st {
+	case StateProposing:
+		return w.addNextTarget(input)
+	case StateGuessing:
+		return w.addNewGuess(input)
+	case StateSolved:
+		// the solution was not published, e.g. the proposal was refused
+		return w.resubmit(input)
+	case StateRoundTimedOut:
+		// move the game on with a new word
+		return w.timeout(input)
 	default:
-		// nothing
-		return errors.New("unable to add any new guess")
+		return fmt.Errorf("no input is expected while %s", st)
 	}
-	return nil
 }
 
-func (w *WordGame) addNextTarget(nextWord string, salts []string) error {
-	// check if we are in state 0
-	if atomic.LoadInt32(&w.StateIdx) != int32(0) {
-		return errors.New("unable to add next target, not in state 0")
+// deadline is the time after which we may reveal or skip the round.
+func (w *WordGame) deadline() time.Time {
+	if w.head.PeerID == w.PeerId {
+		return w.head.RevealAfter(w.rules)
 	}
+	return w.head.SkipAfter(w.rules)
+}
 
-	w.NextWord = nextWord
-	// go to state 1
-	atomic.StoreInt32(&w.StateIdx, int32(1))
+// timeoutHint tells when the current round can be revealed or skipped.
+func (w *WordGame) timeoutHint() string {
+	if w.head.PeerID == w.PeerId {
+		return fmt.Sprintf("\nIf nobody guesses it until %s, you may reveal it\n", w.deadline().Format(time.Kitchen))
+	}
+	return fmt.Sprintf("\nIf nobody guesses it until %s, you may skip it\n", w.deadline().Format(time.Kitchen))
+}
+
+// timeout reveals our word or skips someone's, if the round timed out, proposing the 'nextWord' instead.
+func (w *WordGame) timeout(nextWord string) error {
+	if w.rules.Dictionary == "" {
+		if err := w.checkWord(nextWord); err != nil {
+			return err
+		}
+	}
+
+	w.lk.Lock()
+	err := w.transition(StateRoundTimedOut, nextWord)
+	w.lk.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return w.backend.Timeout(w.ctx, nextWord)
+}
+
+func (w *WordGame) addNextTarget(nextWord string) error {
+	if err := w.checkWord(nextWord); err != nil {
+		return err
+	}
+
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	w.nextWord = nextWord
+	return w.transition(StateGuessing, nextWord)
+}
+
+// checkWord checks the length of the 'word' in letters and that it is written in the alphabet of the network.
+func (w *WordGame) checkWord(word string) error {
+	if l := dictionary.Len(word); l < w.rules.MinWordLen || l > w.rules.MaxWordLen {
+		return fmt.Errorf("the word must be from %d to %d letters long", w.rules.MinWordLen, w.rules.MaxWordLen)
+	}
+	if w.alphabet != nil && !w.alphabet.Contains(word) {
+		return fmt.Errorf("the word must consist of letters '%s'", w.alphabet)
+	}
+	if l := w.wordLen(); l != 0 && dictionary.Len(word) != l {
+		return fmt.Errorf("the word must be %d letters long, as you chose", l)
+	}
 	return nil
 }
 
 func (w *WordGame) WasGuessed() bool {
-	// check if we have already guessed 5 times or if guess correct
-	for _, word := range w.AttemptedWords {
-		comp, err := model.VerifyString(word, w.Target)
-		if err != nil {
-			continue
-		}
-		if IsGuessSuccess(comp) {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	for _, word := range w.attemptedWords {
+		if IsGuessSuccess(w.isCorrect[word]) {
 			return true
 		}
 	}
@@ -143,45 +475,106 @@ func (w *WordGame) WasGuessed() bool {
 }
 
 func (w *WordGame) addNewGuess(guessedWord string) error {
-	// check if we are in state 1
-	if atomic.LoadInt32(&w.StateIdx) != int32(1) {
-		return errors.New("unable to add next target, not in state 1")
+	if l := len(w.Target.Chars); dictionary.Len(guessedWord) != l {
+		return fmt.Errorf("the word must be %d letters long", l)
+	}
+	if w.alphabet != nil && !w.alphabet.Contains(guessedWord) {
+		return fmt.Errorf("the word must consist of letters '%s'", w.alphabet)
 	}
-
-	// add the new word to the list of Attempted, Verify if its the correct word, add to the map the result
-	w.AttemptedWords = append(w.AttemptedWords, guessedWord)
 
 	correct, err := model.VerifyString(guessedWord, w.Target)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	w.isCorrect[guessedWord] = correct
+	w.lk.Lock()
+	if w.state != StateGuessing {
+		w.lk.Unlock()
+		return fmt.Errorf("unable to guess while %s", w.state)
+	}
+	if w.head.IsHard(w.rules) {
+		if err := model.CheckHardMode(guessedWord, w.attemptedWords, w.Target); err != nil {
+			w.lk.Unlock()
+			return err
+		}
+	}
 
-	comp, err := model.VerifyString(guessedWord, w.Target)
+	// the attempt is saved before publishing it, so that restarting never gives more attempts
+	err = w.attempt(guessedWord, correct)
+	proposal := w.nextWord
+	w.lk.Unlock()
 	if err != nil {
 		return err
 	}
 
-	if IsGuessSuccess(comp) {
-		atomic.StoreInt32(&w.StateIdx, int32(2)) // Congrats, wait untill someone guesses your word
+	// publish the guess without holding the lock, as it may take a while
+	return w.backend.SubmitGuess(w.ctx, guessedWord, proposal)
+}
+
+// resubmit publishes our solution of the round again, proposing the 'nextWord' instead.
+func (w *WordGame) resubmit(nextWord string) error {
+	if w.rules.Dictionary == "" {
+		if err := w.checkWord(nextWord); err != nil {
+			return err
+		}
 	}
 
-	// check if we did all the attempts
-	if len(w.AttemptedWords) == maxAttempts && !IsGuessSuccess(comp) {
-		atomic.StoreInt32(&w.StateIdx, int32(3)) // Wait untill you can play again
+	head, err := w.backend.CurrentRound(w.ctx)
+	if err != nil {
+		return err
+	}
+	if head.Height != w.head.Height {
+		return fmt.Errorf("the round is over already")
 	}
 
-	// send the msg over the channel to notify the service
-	currentGuess := guess{
-		guessedWord,
-		w.NextWord,
+	w.lk.Lock()
+	if w.state != StateSolved {
+		w.lk.Unlock()
+		return fmt.Errorf("unable to publish the solution while %s", w.state)
 	}
-	fmt.Println("sending guess")
-	err = w.serv.Guess(w.ctx, currentGuess.Guess, currentGuess.Proposal)
+	w.nextWord = nextWord
+	solution := w.attemptedWords[len(w.attemptedWords)-1]
+	err = w.transition(StateSolved, nextWord)
+	w.lk.Unlock()
 	if err != nil {
 		return err
 	}
-	fmt.Println("after guessing")
-	return nil
+
+	return w.backend.SubmitGuess(w.ctx, solution, nextWord)
+}
+
+// AddTeamGuess records the guess 'word' of a member of our team, taking one of the shared attempts.
+func (w *WordGame) AddTeamGuess(word string) error {
+	word = dictionary.Normalize(strings.ToLower(word))
+	correct, err := model.VerifyString(word, w.Target)
+	if err != nil {
+		return err
+	}
+
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	if w.state != StateGuessing {
+		return fmt.Errorf("unable to guess while %s", w.state)
+	}
+	return w.attempt(word, correct)
+}
+
+// attempt adds the guess 'word' with its 'correct' letters, moving the game on if it solves the word
+// or runs out of attempts. Must be called under the lock.
+func (w *WordGame) attempt(word string, correct []bool) error {
+	if len(w.attemptedWords) >= w.rules.MaxAttempts {
+		return fmt.Errorf("no more attempts left")
+	}
+
+	next := StateGuessing
+	switch {
+	case IsGuessSuccess(correct):
+		next = StateSolved // Congrats, wait untill the network accepts the solution
+	case len(w.attemptedWords)+1 == w.rules.MaxAttempts:
+		next = StateOutOfAttempts // Wait untill you can play again
+	}
+
+	w.attemptedWords = append(w.attemptedWords, word)
+	w.isCorrect[word] = correct
+	return w.transition(next, word)
 }
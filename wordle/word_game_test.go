@@ -2,115 +2,326 @@ package wordle
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
-	"github.com/p2p-games/wordle/model"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/require"
+
+	"github.com/p2p-games/wordle/model"
 )
 
 func TestStateTransition(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	require := require.New(t)
-	salts := []string{"a", "b", "c", "d", "e"}
-	target := &model.Word{
-		Chars: []*model.Char{
-			{
-				Salt: "a",
-				Hash: "8693873cd8f8a2d9c7c596477180f851e525f4eaf55a4",
-			},
-			{
-				Salt: "b",
-				Hash: "fce2551fcc23040870d151006816cc39d3831abff948d",
-			},
-			{
-				Salt: "c",
-				Hash: "ef07b359570add31929a5422d400b16c7c84e35644cb2",
-			},
-			{
-				Salt: "d",
-				Hash: "e5a08ffd3d7509c66e79642edbdcd8ed889269a7164c7",
-			},
-			{
-				Salt: "e",
-				Hash: "3ab80789f271e9870121d65c28808d23c3ee2f4d1498e",
-			},
-		},
-	}
-
-	guessMsgC := make(chan guess)
-	go func() {
-		for {
-			select {
-			case _ = <-guessMsgC:
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
-	chars, err := model.GetChars("hello", salts)
-	word := &model.Word{Chars: chars}
+	backend := newTestBackend(ctx, t, "hello")
+	head, err := backend.CurrentRound(ctx)
 	require.NoError(err)
-
-	wordGame := NewWordGame("peerID1", "peerID2", word, guessMsgC)
+	wordGame := NewWordGame(ctx, backend, head)
 
 	t.Log(wordGame.ComposeStateUI())
 
-	require.Equal(wordGame.StateIdx, int32(0))
-	require.Equal(*wordGame.Target, *target)
+	require.Equal(StateProposing, wordGame.State())
+	require.Equal(*head.Proposal, *wordGame.Target)
 
 	// add the next add new input
 	err = wordGame.NewStdinInput("nextt")
 	require.NoError(err)
-	require.Equal(wordGame.StateIdx, int32(1))
-	require.Equal(wordGame.NextWord, "nextt")
+	require.Equal(StateGuessing, wordGame.State())
+	require.Equal("nextt", wordGame.NextWord())
 
-	// add the next add new input
-	/*
-		err = wordGame.NewStdinInput("/ipfs")
-		require.NoError(err)
-		require.Equal(wordGame.StateIdx, int32(0))
-		require.Equal(0, len(wordGame.AttemptedWords))
-	*/
 	for i, word := range []string{"Guess", "ramon", "pedro", "lucas"} {
 		// add the next add new input
 		err = wordGame.NewStdinInput(word)
 		require.NoError(err)
-		require.Equal(wordGame.StateIdx, int32(1))
-		require.Equal(wordGame.AttemptedWords[i], strings.ToLower(word))
+		require.Equal(StateGuessing, wordGame.State())
+		require.Equal(strings.ToLower(word), wordGame.AttemptedWords()[i])
 		t.Log(wordGame.ComposeStateUI())
-		//time.Sleep(10 * time.Second)
-		//ClearTerminal()
 	}
 
 	// add the next add new input
 	err = wordGame.NewStdinInput("hello")
 	require.NoError(err)
-	require.Equal(wordGame.StateIdx, int32(2))
-	require.Equal(wordGame.AttemptedWords[4], "hello")
+	require.Equal(StateSolved, wordGame.State())
+	require.Equal("hello", wordGame.AttemptedWords()[4])
 
 	t.Log(wordGame.ComposeStateUI())
-	//time.Sleep(10 * time.Second)
-	//ClearTerminal()
 
 	// add the next add new input
 	err = wordGame.NewStdinInput("juanx")
 	require.Error(err)
-	require.Equal(wordGame.StateIdx, int32(2))
-	require.Equal(len(wordGame.AttemptedWords), 5)
-
-	t.Log(wordGame.ComposeStateUI())
-	//time.Sleep(10 * time.Second)
-	//ClearTerminal()
+	require.Equal(StateSolved, wordGame.State())
+	require.Len(wordGame.AttemptedWords(), 5)
 
 	// try to see if it was success
 	guessed := wordGame.WasGuessed()
 	require.Equal(guessed, true)
 
-	wordGame2 := NewWordGame("peerID1", "peerID1", word, guessMsgC)
-	require.Equal(int32(2), wordGame2.StateIdx)
+	// the solution starts our round
+	next, err := backend.CurrentRound(ctx)
+	require.NoError(err)
+	require.Equal(backend.ID(), next.PeerID)
+	wordGame2 := NewWordGame(ctx, backend, next)
+	require.Equal(StateSpectating, wordGame2.State())
+
+	// restarting continues the game
+	restarted := NewWordGame(ctx, backend, head)
+	require.Equal(StateProposing, restarted.State())
+	require.NoError(restarted.Restore())
+	require.Equal(StateSolved, restarted.State())
+	require.Equal("nextt", restarted.NextWord())
+	require.Equal(wordGame.AttemptedWords(), restarted.AttemptedWords())
+	require.Len(restarted.Events(), len(wordGame.Events()))
+	require.True(restarted.WasGuessed())
 
-	close(guessMsgC)
 	cancel()
 }
+
+func TestWordGame_Events(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := newTestBackend(ctx, t, "hello")
+	head := playTestRound(ctx, t, backend, "hello", "world")
+	rules := backend.Genesis().Rules
+
+	game := NewWordGame(ctx, backend, head)
+	require.NoError(t, game.NewStdinInput("after"))
+	for i := 0; i < MaxApptemps; i++ {
+		require.NoError(t, game.NewStdinInput("wrong"))
+	}
+	require.Equal(t, StateOutOfAttempts, game.State())
+	require.Error(t, game.NewStdinInput("world"))
+
+	// the round times out for others only after the skip time
+	require.False(t, game.Tick(head.RevealAfter(rules)))
+	require.True(t, game.Tick(head.SkipAfter(rules)))
+	require.False(t, game.Tick(head.SkipAfter(rules)))
+	require.Equal(t, StateRoundTimedOut, game.State())
+
+	events := game.Events()
+	require.Len(t, events, MaxApptemps+2)
+	require.Equal(t,
+		GameEvent{Time: events[0].Time, From: StateProposing, To: StateGuessing, Input: "after"}, events[0])
+	require.Equal(t, StateOutOfAttempts, events[MaxApptemps].To)
+	require.Equal(t, StateRoundTimedOut, events[MaxApptemps+1].To)
+	// the backend refuses to skip the round before its time
+	require.ErrorIs(t, game.NewStdinInput("again"), errTooEarly)
+}
+
+func TestWordGame_Concurrent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := newTestBackend(ctx, t, "hello")
+	head := playTestRound(ctx, t, backend, "hello", "world")
+
+	game := NewWordGame(ctx, backend, head)
+	require.NoError(t, game.NewStdinInput("after"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < MaxApptemps*2; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = game.NewStdinInput("wrong")
+		}()
+		go func() {
+			defer wg.Done()
+			game.ComposeStateUI()
+			game.Tick(time.Now())
+			game.WasGuessed()
+		}()
+	}
+	wg.Wait()
+
+	// no matter the interleaving, attempts never exceed the limit
+	require.Len(t, game.AttemptedWords(), MaxApptemps)
+	require.Len(t, game.Events(), MaxApptemps+1)
+}
+
+func TestWordGame_TeamGuess(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := newTestBackend(ctx, t, "hello")
+	head := playTestRound(ctx, t, backend, "hello", "world")
+
+	game := NewWordGame(ctx, backend, head)
+	// guesses of teammates count only while guessing
+	require.Error(t, game.AddTeamGuess("wrong"))
+	require.NoError(t, game.NewStdinInput("after"))
+
+	// guesses of teammates take the shared attempts
+	for i := 0; i < MaxApptemps-1; i++ {
+		require.NoError(t, game.AddTeamGuess("wrong"))
+	}
+	require.NoError(t, game.NewStdinInput("wrong"))
+	require.Equal(t, StateOutOfAttempts, game.State())
+	require.Len(t, game.AttemptedWords(), MaxApptemps)
+
+	game = NewWordGame(ctx, backend, head)
+	require.NoError(t, game.NewStdinInput("after"))
+	require.NoError(t, game.AddTeamGuess("World"))
+	require.Equal(t, StateSolved, game.State())
+	require.True(t, game.WasGuessed())
+}
+
+func TestWordGame_HardMode(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g, err := model.NewGenesis("test", "hello", model.Rules{
+		MinWordLen:  MinWordLen,
+		MaxWordLen:  MaxWordLen,
+		MaxAttempts: MaxApptemps,
+		HardMode:    true,
+	})
+	require.NoError(t, err)
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	backend, err := NewMemoryBackend(ctx, g, key)
+	require.NoError(t, err)
+	head := playTestRound(ctx, t, backend, "hello", "world")
+	events := backend.Events(ctx)
+
+	game := NewWordGame(ctx, backend, head)
+	require.NoError(t, game.NewStdinInput("after"))
+	require.NoError(t, game.NewStdinInput("wrong"))
+	// 'w' must stay, while 'r' and 'o' must be used
+	require.Error(t, game.NewStdinInput("award"))
+	require.NoError(t, game.NewStdinInput("worry"))
+	require.Equal(t, []string{"wrong", "worry"}, game.AttemptedWords())
+
+	// the guesses carry the earlier attempts, so that others can check them
+	for _, attempts := range [][]string{{}, {"wrong"}} {
+		evt := (<-events).(EvtGuessAttempt)
+		require.False(t, evt.Successful)
+		require.Equal(t, attempts, evt.Header.Attempts)
+	}
+	require.NoError(t, game.NewStdinInput("world"))
+	require.Equal(t, StateSolved, game.State())
+	for {
+		if evt, ok := (<-events).(EvtGuessAttempt); ok && evt.Successful {
+			require.Equal(t, []string{"wrong", "worry"}, evt.Header.Attempts)
+			break
+		}
+	}
+}
+
+func TestWordGame_WordLen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := newTestBackend(ctx, t, "hello")
+	head, err := backend.CurrentRound(ctx)
+	require.NoError(t, err)
+	game := NewWordGame(ctx, backend, head)
+
+	require.Error(t, game.SetWordLen(MinWordLen-1))
+	require.Error(t, game.SetWordLen(MaxWordLen+1))
+	require.NoError(t, game.SetWordLen(6))
+	require.Equal(t, 6, backend.WordLen())
+
+	// the proposal must be of the chosen length
+	require.Error(t, game.NewStdinInput("world"))
+	require.Equal(t, StateProposing, game.State())
+	require.NoError(t, game.NewStdinInput("planet"))
+	require.Equal(t, StateGuessing, game.State())
+	require.Contains(t, game.ComposeStateUI(), "It has 5 letters")
+
+	// the length can not change under the word we propose
+	require.Error(t, game.SetWordLen(5))
+	require.NoError(t, game.SetWordLen(0))
+	require.NoError(t, game.SetWordLen(6))
+
+	require.NoError(t, game.NewStdinInput("hello"))
+	require.Equal(t, StateSolved, game.State())
+	head, err = backend.CurrentRound(ctx)
+	require.NoError(t, err)
+	require.Len(t, head.Proposal.Chars, 6)
+}
+
+func TestWordGame_Resubmit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := &failingBackend{MemoryBackend: newTestBackend(ctx, t, "hello"), fails: 1}
+	head, err := backend.CurrentRound(ctx)
+	require.NoError(t, err)
+	game := NewWordGame(ctx, backend, head)
+
+	require.NoError(t, game.NewStdinInput("world"))
+	require.Error(t, game.NewStdinInput("hello"))
+	require.Equal(t, StateSolved, game.State())
+
+	// the solve is kept, so publishing it is retried with the next input as the proposal
+	require.Error(t, game.NewStdinInput("no"))
+	require.NoError(t, game.NewStdinInput("apple"))
+	require.Equal(t, StateSolved, game.State())
+	require.Equal(t, "apple", game.NextWord())
+	require.Len(t, game.AttemptedWords(), 1)
+
+	next, err := backend.CurrentRound(ctx)
+	require.NoError(t, err)
+	require.Equal(t, head.Height+1, next.Height)
+	require.Equal(t, "hello", next.Solution)
+
+	// once the round is over, there is nothing to retry
+	require.Error(t, game.NewStdinInput("berry"))
+}
+
+// failingBackend is a MemoryBackend failing to submit the first 'fails' guesses.
+type failingBackend struct {
+	*MemoryBackend
+	fails int
+}
+
+func (b *failingBackend) SubmitGuess(ctx context.Context, guess, proposal string) error {
+	if b.fails > 0 {
+		b.fails--
+		return errors.New("publishing failed")
+	}
+	return b.MemoryBackend.SubmitGuess(ctx, guess, proposal)
+}
+
+// newTestBackend starts a MemoryBackend, which first word is the 'word'.
+func newTestBackend(ctx context.Context, t *testing.T, word string) *MemoryBackend {
+	g, err := model.NewGenesis("test", word, model.Rules{
+		MinWordLen:  MinWordLen,
+		MaxWordLen:  MaxWordLen,
+		MaxAttempts: MaxApptemps,
+	})
+	require.NoError(t, err)
+
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	backend, err := NewMemoryBackend(ctx, g, key)
+	require.NoError(t, err)
+	return backend
+}
+
+// playTestRound makes another player solve the current 'word' and propose the 'next' one,
+// returning the header starting the new round.
+func playTestRound(ctx context.Context, t *testing.T, backend *MemoryBackend, word, next string) *model.Header {
+	_, pub, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	id, err := peer.IDFromPublicKey(pub)
+	require.NoError(t, err)
+
+	head, err := backend.CurrentRound(ctx)
+	require.NoError(t, err)
+	prop, err := model.NewProposal(next, model.DefaultSaltLength)
+	require.NoError(t, err)
+	head, err = model.NewHeader(head, word, prop, id.String())
+	require.NoError(t, err)
+
+	require.NoError(t, backend.Play(ctx, head))
+	return head
+}